@@ -23,6 +23,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/markustenghamn/nordeago"
 	"github.com/markustenghamn/nordeago/ais"
@@ -43,6 +44,7 @@ func main() {
 
 	// Create the client by calling InitClient with your clientID and clientSecret
 	client := nordeago.InitClient(clientID, clientSecret, redirectURI)
+	ctx := context.Background()
 
 	// Build the AuthRequestDecoupled
 	// Documentation can be found here https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#authorize
@@ -58,7 +60,7 @@ func main() {
 	}
 
 	// Pass the request to the StartAuthDecoupled of the client to make the request
-	authResponse, err := ina.StartAuthDecoupled(&client, authRequest)
+	authResponse, err := ina.StartAuthDecoupled(ctx, &client, authRequest)
 
 	// Check for any errors
 	if err != nil {
@@ -70,7 +72,7 @@ func main() {
 		time.Sleep(1 * time.Second)
 		fmt.Printf(".")
 		// We get the order_ref and poll for access token
-		_, statusCode, err := ina.PollForAuthCodeDecoupled(&client, authResponse.OrderRef)
+		_, statusCode, err := ina.PollForAuthCodeDecoupled(ctx, &client, authResponse.OrderRef)
 
 		// Check for any errors
 		if err != nil {
@@ -90,7 +92,7 @@ func main() {
 		RedirectURI: client.RedirectURL,
 	}
 
-	_, err = ina.RetrieveAccessTokenDecoupled(&client, retrieveAccessTokenRequest)
+	_, err = ina.RetrieveAccessTokenDecoupled(ctx, &client, retrieveAccessTokenRequest)
 
 	// Check for any errors
 	if err != nil {
@@ -110,20 +112,20 @@ func main() {
 		AccountNumber:                ais.AccountNumber{Type: "IBAN", Value: "FI1350001520000081"},
 		AccountNumbers:               []ais.AccountNumber{{Type: "IBAN", Value: "FI1350001520000081"}},
 		AccountType:                  "Current",
-		AvailableBalance:             "1000.00",
-		BookedBalance:                "1000.00",
+		AvailableBalance:             nordeago.MustParseDecimal("1000.00"),
+		BookedBalance:                nordeago.MustParseDecimal("1000.00"),
 		Country:                      "FI",
-		CreditLimit:                  "1000.00",
-		Created:                      "2017-06-01",
+		CreditLimit:                  nordeago.MustParseDecimal("1000.00"),
+		Created:                      nordeago.MustParseDate("2017-06-01"),
 		Currency:                     "EUR",
-		LatestTransactionBookingDate: "2017-06-01",
+		LatestTransactionBookingDate: nordeago.MustParseDate("2017-06-01"),
 		OwnerName:                    "no example",
 		Product:                      "Example Product",
 		Status:                       "OPEN",
-		ValueDatedBalance:            "123.45",
+		ValueDatedBalance:            nordeago.MustParseDecimal("123.45"),
 	}
 
-	created, err := ais.CreateAccount(&client, createAccountRequest)
+	created, err := ais.CreateAccount(ctx, &client, createAccountRequest)
 
 	// print our response
 	if created {
@@ -23,6 +23,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/markustenghamn/nordeago"
 	"github.com/markustenghamn/nordeago/ais"
@@ -42,6 +43,7 @@ func main() {
 
 	// Create the client by calling InitClient with your clientID and clientSecret
 	client := nordeago.InitClient(clientID, clientSecret, redirectURI)
+	ctx := context.Background()
 
 	// Build the AuthRequestDecoupled
 	// Documentation can be found here https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#authorize
@@ -57,7 +59,7 @@ func main() {
 	}
 
 	// Pass the request to the StartAuthDecoupled of the client to make the request
-	authResponse, err := ina.StartAuthDecoupled(&client, authRequest)
+	authResponse, err := ina.StartAuthDecoupled(ctx, &client, authRequest)
 
 	// Check for any errors
 	if err != nil {
@@ -69,7 +71,7 @@ func main() {
 		time.Sleep(1 * time.Second)
 		fmt.Printf(".")
 		// We get the order_ref and poll for access token
-		_, statusCode, err := ina.PollForAuthCodeDecoupled(&client, authResponse.OrderRef)
+		_, statusCode, err := ina.PollForAuthCodeDecoupled(ctx, &client, authResponse.OrderRef)
 
 		// Check for any errors
 		if err != nil {
@@ -89,7 +91,7 @@ func main() {
 		RedirectURI: client.RedirectURL,
 	}
 
-	_, err = ina.RetrieveAccessTokenDecoupled(&client, retrieveAccessTokenRequest)
+	_, err = ina.RetrieveAccessTokenDecoupled(ctx, &client, retrieveAccessTokenRequest)
 
 	// Check for any errors
 	if err != nil {
@@ -98,7 +100,7 @@ func main() {
 
 	// Make request to list our accounts
 
-	accountListResponse, err := ais.ListAccounts(&client)
+	accountListResponse, err := ais.ListAccounts(ctx, &client)
 
 	// print our response
 	fmt.Printf("%+v\n", accountListResponse)
@@ -23,6 +23,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/markustenghamn/nordeago"
 	"github.com/markustenghamn/nordeago/ina"
@@ -39,6 +40,7 @@ func main() {
 
 	// Create the client by calling InitClient with your clientID and clientSecret
 	client := nordeago.InitClient(clientID, clientSecret, redirectURI)
+	ctx := context.Background()
 
 	// Build the AuthRequestDecoupled
 	// Documentation can be found here https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#authorize
@@ -54,7 +56,7 @@ func main() {
 	}
 
 	// Pass the request to the StartAuthDecoupled of the client to make the request
-	authResponse, err := ina.StartAuthDecoupled(&client, authRequest)
+	authResponse, err := ina.StartAuthDecoupled(ctx, &client, authRequest)
 
 	// Check for any errors
 	if err != nil {
@@ -23,6 +23,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/markustenghamn/nordeago"
 	"github.com/markustenghamn/nordeago/ina"
@@ -41,6 +42,7 @@ func main() {
 
 	// Create the client by calling InitClient with your clientID and clientSecret
 	client := nordeago.InitClient(clientID, clientSecret, redirectURI)
+	ctx := context.Background()
 
 	// Build the AuthRequestDecoupled
 	// Documentation can be found here https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#authorize
@@ -56,7 +58,7 @@ func main() {
 	}
 
 	// Pass the request to the StartAuthDecoupled of the client to make the request
-	authResponse, err := ina.StartAuthDecoupled(&client, authRequest)
+	authResponse, err := ina.StartAuthDecoupled(ctx, &client, authRequest)
 
 	// Check for any errors
 	if err != nil {
@@ -68,7 +70,7 @@ func main() {
 		time.Sleep(1 * time.Second)
 		fmt.Printf(".")
 		// We get the order_ref and poll for access token
-		_, statusCode, err := ina.PollForAuthCodeDecoupled(&client, authResponse.OrderRef)
+		_, statusCode, err := ina.PollForAuthCodeDecoupled(ctx, &client, authResponse.OrderRef)
 
 		// Check for any errors
 		if err != nil {
@@ -88,14 +90,14 @@ func main() {
 		RedirectURI: client.RedirectURL,
 	}
 
-	_, err = ina.RetrieveAccessTokenDecoupled(&client, retrieveAccessTokenRequest)
+	_, err = ina.RetrieveAccessTokenDecoupled(ctx, &client, retrieveAccessTokenRequest)
 
 	// Check for any errors
 	if err != nil {
 		panic(err)
 	}
 
-	result, err := ina.GetAssets(&client)
+	result, err := ina.GetAssets(ctx, &client)
 
 	// Check for any errors
 	if err != nil {
@@ -105,4 +107,9 @@ func main() {
 	// print our response
 	fmt.Printf("%+v\n", result)
 
+	// Check which accounts we are allowed to fetch transactions for before calling the ais package
+	for _, consent := range result.Consents() {
+		fmt.Printf("%s transactions: %t, balances: %t, payments: %t\n", consent.Account.AccountID, consent.CanListTransactions(), consent.CanReadBalances(), consent.CanInitiatePayments())
+	}
+
 }
@@ -23,10 +23,12 @@
 package ais
 
 import (
-	"errors"
-	"github.com/google/go-querystring/query"
-	"github.com/markustenghamn/nordeago"
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/markustenghamn/nordeago"
 )
 
 // Note: Different countries can have different variables for different methods.
@@ -34,149 +36,88 @@ import (
 // ListAccounts lists the accounts for the user
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#accountList
-func ListAccounts(c *nordeago.Client) (ListAccountsResponse, error) {
-	responseType := ListAccountsResponse{}
-	result := nordeago.Result{Response: &responseType}
-	endpoint := "/accounts"
-
-	response, err := c.GetWithAccessToken(endpoint, nil)
-
-	if err != nil {
-		return responseType, err
-	}
-
-	defer response.Body.Close()
-
-	_, err = c.HandleResponse(response, &result)
-
-	return responseType, err
+func ListAccounts(ctx context.Context, c *nordeago.Client) (ListAccountsResponse, error) {
+	response, _, err := nordeago.Do[ListAccountsResponse](ctx, c, http.MethodGet, "/accounts", nil)
+	return response, err
 }
 
 // CreateAccount for sandbox environment
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#createAccountV2
-func CreateAccount(c *nordeago.Client, request CreateAccountRequest) (bool, error) {
-	result := nordeago.Result{}
-
-	endpoint := "/accounts"
+func CreateAccount(ctx context.Context, c *nordeago.Client, request CreateAccountRequest) (bool, error) {
 	// Returns a 201 status code if created
-
-	response, err := c.PostWithAccessToken(endpoint, request, nil)
-
-	if err != nil {
-		return false, err
-	}
-
-	defer response.Body.Close()
-
-	status, err := c.HandleResponse(response, &result)
-
-	if status == http.StatusCreated {
-		return true, err
-	}
-
-	return false, err
-
+	_, meta, err := nordeago.Do[json.RawMessage](ctx, c, http.MethodPost, "/accounts", request)
+	return meta.StatusCode == http.StatusCreated, err
 }
 
 // GetAccountDetails gets account details for the specified account
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#accountDetails
-func GetAccountDetails(c *nordeago.Client, accountID string) (*AccountDetailed, error) {
-	responseType := &AccountDetailed{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := "/accounts/{{accountId}}"
-	endpoint = nordeago.ReplaceVariable(endpoint, "accountId", accountID)
-
-	response, err := c.GetWithAccessToken(endpoint, nil)
-
+func GetAccountDetails(ctx context.Context, c *nordeago.Client, accountID string) (*AccountDetailed, error) {
+	endpoint, err := nordeago.NewEndpoint("/accounts/{{accountId}}").With("accountId", accountID).Build()
 	if err != nil {
-		return responseType, err
+		return &AccountDetailed{}, err
 	}
 
-	defer response.Body.Close()
-
-	_, err = c.HandleResponse(response, &result)
-
-	return responseType, err
+	response, _, err := nordeago.Do[AccountDetailed](ctx, c, http.MethodGet, endpoint, nil)
+	return &response, err
 }
 
 // DeleteAccount for sandbox environment
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#deleteUserDefinedAccount
-func DeleteAccount(c *nordeago.Client, accountID string) (string, error) {
-	responseType := ""
-	result := nordeago.Result{Response: &responseType}
-
-	endpoint := "/accounts/{{accountId}}"
-	endpoint = nordeago.ReplaceVariable(endpoint, "accountId", accountID)
-
-	response, err := c.DeleteWithAccessToken(endpoint, nil)
-
+func DeleteAccount(ctx context.Context, c *nordeago.Client, accountID string) (string, error) {
+	endpoint, err := nordeago.NewEndpoint("/accounts/{{accountId}}").With("accountId", accountID).Build()
 	if err != nil {
-		return responseType, err
+		return "", err
 	}
 
-	defer response.Body.Close()
-
-	_, err = c.HandleResponse(response, &result)
-
-	return responseType, err
+	response, _, err := nordeago.Do[string](ctx, c, http.MethodDelete, endpoint, nil)
+	return response, err
 }
 
 // GetAccountTransactions gets the transactions for the specified account
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#transactionsList
-func GetAccountTransactions(c *nordeago.Client, accountID string, request GetAccountTransactionsRequest) (*GetAccountTransactionsResponse, error) {
-	responseType := &GetAccountTransactionsResponse{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := "/accounts/{{accountId}}/transactions"
-	endpoint = nordeago.ReplaceVariable(endpoint, "accountId", accountID)
-
-	req, err := http.NewRequest("GET", c.GetFullURL(endpoint), nil)
+func GetAccountTransactions(ctx context.Context, c *nordeago.Client, accountID string, filter TransactionFilter) (*GetAccountTransactionsResponse, error) {
+	v, err := filter.Values()
 	if err != nil {
-		return responseType, err
+		return &GetAccountTransactionsResponse{}, err
 	}
 
-	v, _ := query.Values(request)
-
-	req.URL.RawQuery = v.Encode()
-
-	response, err := c.GetWithAccessToken(req.URL.String(), nil)
-
+	endpoint, err := nordeago.NewEndpoint("/accounts/{{accountId}}/transactions").With("accountId", accountID).WithQuery(v).Build()
 	if err != nil {
-		return responseType, err
+		return &GetAccountTransactionsResponse{}, err
 	}
 
-	defer response.Body.Close()
+	response, meta, err := nordeago.Do[GetAccountTransactionsResponse](ctx, c, http.MethodGet, endpoint, nil)
 
-	_, err = c.HandleResponse(response, &result)
+	// The continuation key can be returned in the group header instead of the response
+	if len(response.ContinuationKey) == 0 {
+		response.ContinuationKey = meta.ContinuationKey()
+	}
 
-	return responseType, err
+	return &response, err
 }
 
 // CreateAccountTransaction creates a transaction
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#createTransaction
-func CreateAccountTransaction(c *nordeago.Client, accountID string, request Transaction) (bool, error) {
-	endpoint := "/accounts/{{accountId}}/transactions"
-	endpoint = nordeago.ReplaceVariable(endpoint, "accountId", accountID)
-	// Returns a 201 status code if created
-
-	response, err := c.PostWithAccessToken(endpoint, request, nil)
-
+func CreateAccountTransaction(ctx context.Context, c *nordeago.Client, accountID string, request Transaction) (bool, error) {
+	endpoint, err := nordeago.NewEndpoint("/accounts/{{accountId}}/transactions").With("accountId", accountID).Build()
 	if err != nil {
 		return false, err
 	}
 
-	defer response.Body.Close()
+	// Returns a 201 status code if created
+	_, meta, err := nordeago.Do[json.RawMessage](ctx, c, http.MethodPost, endpoint, request)
+	if err != nil {
+		return false, err
+	}
 
-	if response.StatusCode == http.StatusCreated {
+	if meta.StatusCode == http.StatusCreated {
 		return true, nil
 	}
 
-	err = errors.New("Request returned status " + string(response.StatusCode))
-	return false, err
+	return false, fmt.Errorf("Request returned status %d", meta.StatusCode)
 }
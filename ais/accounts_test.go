@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func testClient(server *httptest.Server) *nordeago.Client {
+	c := nordeago.InitClient("client-id", "client-secret", "https://httpbin.org/get")
+	c.Protocol = "http://"
+	c.BaseURL = strings.TrimPrefix(server.URL, "http://")
+	c.AccessToken = "token"
+	return &c
+}
+
+func TestGetAccountDetailsEscapesAccountID(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Write([]byte(`{"response":{"_id":"SE/1 2"}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	account, err := GetAccountDetails(context.Background(), c, "SE/1 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid := "/v2/accounts/SE%2F1%202"; path != valid {
+		t.Errorf("request path was incorrect, got: %s, want: %s.", path, valid)
+	}
+	if account.ID != "SE/1 2" {
+		t.Errorf("account id was incorrect, got: %s, want: SE/1 2.", account.ID)
+	}
+}
+
+func TestGetAccountTransactionsRequestPath(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Write([]byte(`{"response":{"transactions":[]}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	if _, err := GetAccountTransactions(context.Background(), c, "FI6593857450293470-EUR", TransactionFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if valid := "/v2/accounts/FI6593857450293470-EUR/transactions"; path != valid {
+		t.Errorf("request path was incorrect, got: %s, want: %s.", path, valid)
+	}
+}
+
+func TestGetAccountTransactionsQuery(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"response":{"transactions":[]}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	filter := TransactionFilter{
+		FromDate:        time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:          time.Date(2018, 10, 31, 0, 0, 0, 0, time.UTC),
+		Language:        "sv",
+		Status:          TransactionStatusBooked,
+		ContinuationKey: "abc/123+=",
+	}
+	if _, err := GetAccountTransactions(context.Background(), c, "41770042136", filter); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := url.Values{
+		"fromDate":        {"2018-10-01"},
+		"toDate":          {"2018-10-31"},
+		"language":        {"sv"},
+		"status":          {"booked"},
+		"continuationKey": {"abc/123+="},
+	}
+	if query.Encode() != valid.Encode() {
+		t.Errorf("query was incorrect, got: %s, want: %s.", query.Encode(), valid.Encode())
+	}
+}
+
+func TestTransactionFilterValidate(t *testing.T) {
+	invalid := []TransactionFilter{
+		{FromDate: time.Date(2018, 10, 31, 0, 0, 0, 0, time.UTC), ToDate: time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{Language: "swedish"},
+		{Status: "unknown"},
+	}
+	for _, filter := range invalid {
+		if err := filter.Validate(); err == nil {
+			t.Errorf("Validate was incorrect, expected an error for %+v.", filter)
+		}
+	}
+}
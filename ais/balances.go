@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// BalanceType is the type of an account balance
+type BalanceType string
+
+// Balance types returned by GetAccountBalances
+const (
+	BalanceTypeBooked     BalanceType = "booked"
+	BalanceTypeAvailable  BalanceType = "available"
+	BalanceTypeValueDated BalanceType = "valueDated"
+)
+
+// Balance is a single balance of an account. AsOf is the date in Stockholm the bank created the response with the
+// balance. It is zero when the date is unknown, which is the case for balances from BalancesFromAccount and for
+// responses without a creation time.
+type Balance struct {
+	Type   BalanceType
+	Amount nordeago.Money
+	AsOf   nordeago.Date
+}
+
+// AccountBalances contains the balances of an account at the time they were retrieved. ValueDated is nil when the
+// API does not return a value dated balance for the account. Use AsOf on each balance for the date reported by the
+// bank, RetrievedAt only tells when the balances were fetched.
+type AccountBalances struct {
+	AccountID   string
+	Currency    string
+	Booked      Balance
+	Available   Balance
+	ValueDated  *Balance
+	CreditLimit nordeago.Money
+	RetrievedAt time.Time // Creation time of the response or the local time if the API did not return it
+}
+
+// balancesConcurrency is the maximum number of requests made at the same time by GetBalances
+const balancesConcurrency = 4
+
+// GetAccountBalances gets the balances of the specified account. Balances are part of the account details in version
+// 2.3 of the Accounts API so this requires the ACCOUNTS_BALANCES scope along with ACCOUNTS_DETAILS.
+//
+// API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Accounts%20API&version=2.3#accountDetails
+func GetAccountBalances(ctx context.Context, c *nordeago.Client, accountID string) (*AccountBalances, error) {
+	endpoint, err := nordeago.NewEndpoint("/accounts/{{accountId}}").With("accountId", accountID).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	account, meta, err := nordeago.Do[AccountDetailed](ctx, c, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := BalancesFromAccount(account)
+	if len(balances.AccountID) == 0 {
+		balances.AccountID = accountID
+	}
+	if !meta.GroupHeader.CreationDateTime.IsZero() {
+		balances.RetrievedAt = meta.GroupHeader.CreationDateTime.Time
+		asOf := nordeago.DateOf(balances.RetrievedAt, nordeago.Stockholm)
+		balances.Booked.AsOf = asOf
+		balances.Available.AsOf = asOf
+		if balances.ValueDated != nil {
+			balances.ValueDated.AsOf = asOf
+		}
+	}
+
+	return balances, nil
+}
+
+// BalancesFromAccount returns the balances of an account that has already been fetched, for example with ListAccounts.
+// The account does not tell when the balances were created so AsOf is left zero on each balance.
+func BalancesFromAccount(account AccountDetailed) *AccountBalances {
+	balances := &AccountBalances{
+		AccountID:   account.ID,
+		Currency:    account.Currency,
+		Booked:      Balance{Type: BalanceTypeBooked, Amount: nordeago.NewMoney(account.BookedBalance, account.Currency)},
+		Available:   Balance{Type: BalanceTypeAvailable, Amount: nordeago.NewMoney(account.AvailableBalance, account.Currency)},
+		CreditLimit: nordeago.NewMoney(account.CreditLimit, account.Currency),
+		RetrievedAt: time.Now(),
+	}
+	if account.ValueDatedBalance.IsSet() {
+		balances.ValueDated = &Balance{Type: BalanceTypeValueDated, Amount: nordeago.NewMoney(account.ValueDatedBalance, account.Currency)}
+	}
+	return balances
+}
+
+// GetBalances gets the balances of all the specified accounts concurrently, for example the accounts returned by
+// ina.AssetsResponse.AccountsWithScope(ina.ScopeAccountsBalances). Balances that could be fetched are returned even
+// if some of the requests failed, the returned error contains all failures.
+func GetBalances(ctx context.Context, c *nordeago.Client, accountIDs ...string) (map[string]*AccountBalances, error) {
+	balances := make(map[string]*AccountBalances, len(accountIDs))
+	var errs []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, balancesConcurrency)
+	for _, accountID := range accountIDs {
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", accountID, ctx.Err()))
+				mu.Unlock()
+				return
+			}
+
+			accountBalances, err := GetAccountBalances(ctx, c, accountID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", accountID, err))
+				return
+			}
+			balances[accountID] = accountBalances
+		}(accountID)
+	}
+	wg.Wait()
+
+	return balances, errors.Join(errs...)
+}
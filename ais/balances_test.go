@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func TestGetBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"groupHeader":{"creationDateTime":"2018-10-01T12:00:00Z"},"response":{"_id":"` + strings.TrimPrefix(r.URL.Path, "/v2/accounts/") + `","currency":"SEK","bookedBalance":"100.00","availableBalance":"90.00","creditLimit":"1000.00","latestTransactionBookingDate":"2018-09-28"}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	balances, err := GetBalances(context.Background(), c, "41770042136", "41770042137", "missing")
+	if err == nil || !strings.Contains(err.Error(), "missing: 404") {
+		t.Errorf("GetBalances error was incorrect, got: %v, want: missing: 404 - Not Found.", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("GetBalances was incorrect, got: %d balances, want: 2.", len(balances))
+	}
+
+	account := balances["41770042137"]
+	if account.Booked.Amount.String() != "100.00 SEK" || account.Available.Amount.String() != "90.00 SEK" || account.CreditLimit.String() != "1000.00 SEK" || account.ValueDated != nil {
+		t.Errorf("balances were incorrect, got: %+v.", account)
+	}
+	for _, balance := range []Balance{account.Booked, account.Available} {
+		if balance.AsOf.String() != "2018-10-01" {
+			t.Errorf("%s balance AsOf was incorrect, got: %s, want: 2018-10-01.", balance.Type, balance.AsOf)
+		}
+	}
+	if account.RetrievedAt.Format("2006-01-02T15:04:05Z") != "2018-10-01T12:00:00Z" {
+		t.Errorf("RetrievedAt was incorrect, got: %s, want: 2018-10-01T12:00:00Z.", account.RetrievedAt)
+	}
+}
+
+func TestBalancesFromAccount(t *testing.T) {
+	account := AccountDetailed{Currency: "SEK", LatestTransactionBookingDate: nordeago.MustParseDate("2018-09-28")}
+	account.BookedBalance = nordeago.MustParseDecimal("100.00")
+
+	balances := BalancesFromAccount(account)
+	if balances.Booked.Amount.String() != "100.00 SEK" || !balances.Booked.AsOf.IsZero() || !balances.Available.AsOf.IsZero() {
+		t.Errorf("BalancesFromAccount was incorrect, got: %+v, want: 100.00 SEK booked without AsOf.", balances)
+	}
+}
@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package categorize
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func debit(narrative string, amount string) *ais.DebitTransaction {
+	return &ais.DebitTransaction{TransactionData: ais.TransactionData{
+		Narrative: narrative,
+		Amount:    nordeago.MustParseDecimal(amount),
+		Currency:  "SEK",
+	}}
+}
+
+func credit(narrative string, amount string) *ais.CreditTransaction {
+	return &ais.CreditTransaction{TransactionData: ais.TransactionData{
+		Narrative: narrative,
+		Amount:    nordeago.MustParseDecimal(amount),
+		Currency:  "SEK",
+	}}
+}
+
+func TestDefaultCategorizer(t *testing.T) {
+	card := debit("ICA NARA ST", "59.90")
+	card.CardNumber = "1234"
+
+	tests := []struct {
+		transaction ais.Transaction
+		category    string
+		rule        string
+	}{
+		{credit("LÖN OKTOBER", "25000"), "Salary", "salary"},
+		{credit("Swish från Anna", "100"), "Swish", "swish-in"},
+		{debit("SWISH BETALNING", "100"), "Swish", "swish-out"},
+		{debit("AUTOGIRO TELIA", "399"), "Direct debit", "autogiro"},
+		{debit("KORTKÖP 240312 ICA NARA ST", "59.90"), "Card purchase", "card-purchase"},
+		{debit("Korttiosto K-Market", "12.30"), "Card purchase", "card-purchase"},
+		{card, "Card purchase", "card-transaction"},
+		{debit("Överföring 1234 56789", "500"), "Transfer", "transfer"},
+		{credit("Ränta", "1.20"), "Interest", "interest"},
+		{debit("Kontantuttag", "200"), "Cash withdrawal", "cash-withdrawal"},
+		{debit("Klarna", "200"), "Other", ""},
+	}
+
+	c := DefaultCategorizer()
+	for _, test := range tests {
+		result := c.Categorize(test.transaction)
+		if result.Category != test.category || result.Rule != test.rule {
+			t.Errorf("Categorize was incorrect for %q, got: %s (%s), want: %s (%s).", test.transaction.Data().Narrative, result.Category, result.Rule, test.category, test.rule)
+		}
+	}
+}
+
+func TestRulesFromJSON(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(`[
+		{"name": "large-rent", "category": "Rent", "type": "DebitTransaction", "counterparty": "^hsb", "minAmount": "5000"},
+		{"name": "small", "category": "Small", "maxAmount": "50", "card": true},
+		{"name": "hsb", "category": "Housing", "counterparty": "hsb"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCategorizer(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rent := debit("", "6500")
+	rent.CounterpartyName = "HSB Stockholm"
+	if result := c.Categorize(rent); result.Rule != "large-rent" || result.Index != 0 {
+		t.Errorf("Categorize was incorrect, got: %+v, want: large-rent.", result)
+	}
+
+	fee := debit("", "150")
+	fee.CounterpartyName = "HSB Stockholm"
+	if result := c.Categorize(fee); result.Rule != "hsb" {
+		t.Errorf("Categorize was incorrect, got: %+v, want: hsb.", result)
+	}
+
+	notCard := debit("", "20")
+	if result := c.Categorize(notCard); result.Matched() {
+		t.Errorf("Categorize was incorrect, got: %+v, want: no match without a card number.", result)
+	}
+
+	data, err := json.Marshal(rules[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"counterparty":"^hsb"`) || strings.Contains(string(data), "narrative") {
+		t.Errorf("MarshalJSON was incorrect, got: %s.", data)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	invalid := []string{
+		`[{"name": "a", "category": "A"}]`,
+		`[{"name": "a", "category": "A", "narrative": "("}]`,
+		`[{"name": "a", "category": "A", "narrative": "x"}, {"name": "a", "category": "B", "narrative": "y"}]`,
+		`[{"name": "a", "category": "A", "minAmount": "10", "maxAmount": "5"}]`,
+		`[{"name": "a", "category": "A", "type": "Credit"}]`,
+	}
+	for _, rules := range invalid {
+		if _, err := ParseRules([]byte(rules)); err == nil {
+			t.Errorf("ParseRules was incorrect for %s, got: nil, want: error.", rules)
+		}
+	}
+}
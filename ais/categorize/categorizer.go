@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package categorize
+
+import "github.com/markustenghamn/nordeago/ais"
+
+// Result is the category of a transaction and the rule that assigned it. Rule is empty and Index is -1 when no rule
+// matched and the default category was used.
+type Result struct {
+	Category string
+	Rule     string
+	Index    int
+}
+
+// Matched returns true if a rule matched the transaction
+func (r Result) Matched() bool {
+	return r.Index >= 0
+}
+
+// Categorizer evaluates rules in order, the first matching rule decides the category of a transaction
+type Categorizer struct {
+	rules []Rule
+	// DefaultCategory is used when no rule matches
+	DefaultCategory string
+}
+
+// NewCategorizer validates the rules and returns a Categorizer that evaluates them in order
+func NewCategorizer(rules []Rule) (*Categorizer, error) {
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+	return &Categorizer{rules: append([]Rule(nil), rules...)}, nil
+}
+
+// Rules returns a copy of the rules in evaluation order
+func (c *Categorizer) Rules() []Rule {
+	return append([]Rule(nil), c.rules...)
+}
+
+// Categorize returns the category of the transaction from the first matching rule
+func (c *Categorizer) Categorize(t ais.Transaction) Result {
+	for i, rule := range c.rules {
+		if rule.Matches(t) {
+			return Result{Category: rule.Category, Rule: rule.Name, Index: i}
+		}
+	}
+	return Result{Category: c.DefaultCategory, Index: -1}
+}
+
+// CategorizeAll returns the results for the transactions in the same order
+func (c *Categorizer) CategorizeAll(transactions []ais.Transaction) []Result {
+	results := make([]Result, len(transactions))
+	for i, t := range transactions {
+		results[i] = c.Categorize(t)
+	}
+	return results
+}
+
+// Classify returns the category of the transaction, it can be used as export.SIEOptions.Classify
+func (c *Categorizer) Classify(t ais.Transaction) string {
+	return c.Categorize(t).Category
+}
@@ -0,0 +1,47 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package categorize
+
+import _ "embed"
+
+// defaultRules contains rules for the transaction texts used by Nordic banks in Swedish, Finnish, Norwegian and Danish
+//
+//go:embed default_rules.json
+var defaultRules []byte
+
+// DefaultRules returns the default Nordic rules which recognise salaries, Swish, MobilePay, Vipps, direct debits
+// such as autogiro, bill payments, tax, interest, fees, cash withdrawals, card purchases and transfers
+func DefaultRules() []Rule {
+	rules, err := ParseRules(defaultRules)
+	if err != nil {
+		panic("invalid default rules: " + err.Error())
+	}
+	return rules
+}
+
+// DefaultCategorizer returns a Categorizer with the default Nordic rules and the default category Other
+func DefaultCategorizer() *Categorizer {
+	c, _ := NewCategorizer(DefaultRules())
+	c.DefaultCategory = "Other"
+	return c
+}
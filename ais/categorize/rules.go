@@ -0,0 +1,234 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package categorize assigns categories to account transactions using ordered rules. Rules are usually loaded from
+// JSON and the first rule that matches a transaction decides its category.
+package categorize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// Pattern is a regular expression matched case-insensitively, it is a string in JSON
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+// NewPattern compiles a case-insensitive regular expression
+func NewPattern(expr string) (Pattern, error) {
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return Pattern{}, err
+	}
+	return Pattern{re: re}, nil
+}
+
+// MustPattern is like NewPattern but panics if the expression can not be compiled
+func MustPattern(expr string) Pattern {
+	p, err := NewPattern(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// IsSet returns true if the pattern has an expression
+func (p Pattern) IsSet() bool {
+	return p.re != nil
+}
+
+// MatchString returns true if the text matches the pattern
+func (p Pattern) MatchString(s string) bool {
+	return p.re != nil && p.re.MatchString(s)
+}
+
+// String returns the expression of the pattern
+func (p Pattern) String() string {
+	if p.re == nil {
+		return ""
+	}
+	return strings.TrimPrefix(p.re.String(), "(?i)")
+}
+
+// MarshalJSON writes the pattern as a string
+func (p Pattern) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON compiles the pattern from a string, an empty string leaves the pattern unset
+func (p *Pattern) UnmarshalJSON(data []byte) error {
+	var expr string
+	if err := json.Unmarshal(data, &expr); err != nil {
+		return err
+	}
+	if len(expr) == 0 {
+		*p = Pattern{}
+		return nil
+	}
+	compiled, err := NewPattern(expr)
+	if err != nil {
+		return err
+	}
+	*p = compiled
+	return nil
+}
+
+// Rule assigns a category to the transactions matching all of its conditions. Unset conditions are ignored but a rule
+// must have at least one condition.
+type Rule struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+
+	Narrative       Pattern `json:"narrative,omitzero"`
+	Counterparty    Pattern `json:"counterparty,omitzero"`
+	Message         Pattern `json:"message,omitzero"`
+	TypeDescription Pattern `json:"typeDescription,omitzero"`
+	// Text matches any of the narrative, counterparty, message and type description
+	Text Pattern `json:"text,omitzero"`
+
+	// TransactionType is CreditTransaction or DebitTransaction
+	TransactionType string `json:"type,omitempty"`
+	// MinAmount and MaxAmount limit the absolute amount of the transaction, both are inclusive
+	MinAmount nordeago.Decimal `json:"minAmount,omitzero"`
+	MaxAmount nordeago.Decimal `json:"maxAmount,omitzero"`
+	Currency  string           `json:"currency,omitempty"`
+	// Card requires the transaction to be a card transaction when true and not a card transaction when false
+	Card *bool `json:"card,omitempty"`
+}
+
+// Validate checks that the rule has a name, a category and at least one condition
+func (r Rule) Validate() error {
+	if len(r.Name) == 0 {
+		return errors.New("rule has no name")
+	}
+	if len(r.Category) == 0 {
+		return fmt.Errorf("rule %s has no category", r.Name)
+	}
+	switch r.TransactionType {
+	case "", ais.TransactionTypeCredit, ais.TransactionTypeDebit:
+	default:
+		return fmt.Errorf("rule %s has unknown transaction type %q", r.Name, r.TransactionType)
+	}
+	if r.MinAmount.IsSet() && r.MaxAmount.IsSet() && r.MinAmount.Cmp(r.MaxAmount) > 0 {
+		return fmt.Errorf("rule %s has a minimum amount larger than the maximum amount", r.Name)
+	}
+
+	conditions := []bool{
+		r.Narrative.IsSet(), r.Counterparty.IsSet(), r.Message.IsSet(), r.TypeDescription.IsSet(), r.Text.IsSet(),
+		len(r.TransactionType) > 0, r.MinAmount.IsSet(), r.MaxAmount.IsSet(), len(r.Currency) > 0, r.Card != nil,
+	}
+	for _, set := range conditions {
+		if set {
+			return nil
+		}
+	}
+	return fmt.Errorf("rule %s has no conditions", r.Name)
+}
+
+// Matches returns true if the transaction matches all conditions of the rule
+func (r Rule) Matches(t ais.Transaction) bool {
+	data := t.Data()
+
+	if len(r.TransactionType) > 0 && r.TransactionType != t.TransactionType() {
+		return false
+	}
+	if len(r.Currency) > 0 && !strings.EqualFold(r.Currency, data.Currency) {
+		return false
+	}
+	if r.Card != nil && *r.Card != (len(data.CardNumber) > 0) {
+		return false
+	}
+
+	amount := data.Amount.Abs()
+	if r.MinAmount.IsSet() && amount.Cmp(r.MinAmount) < 0 {
+		return false
+	}
+	if r.MaxAmount.IsSet() && amount.Cmp(r.MaxAmount) > 0 {
+		return false
+	}
+
+	patterns := []struct {
+		pattern Pattern
+		value   string
+	}{
+		{r.Narrative, data.Narrative},
+		{r.Counterparty, data.CounterpartyName},
+		{r.Message, data.Message},
+		{r.TypeDescription, data.TypeDescription},
+	}
+	for _, p := range patterns {
+		if p.pattern.IsSet() && !p.pattern.MatchString(p.value) {
+			return false
+		}
+	}
+
+	if r.Text.IsSet() {
+		for _, value := range []string{data.Narrative, data.CounterpartyName, data.Message, data.TypeDescription} {
+			if r.Text.MatchString(value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ParseRules decodes a JSON array of rules and validates them
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return rules, validateRules(rules)
+}
+
+// LoadRules reads a JSON array of rules, see ParseRules
+func LoadRules(r io.Reader) ([]Rule, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(data)
+}
+
+// validateRules validates each rule and checks that the rule names are unique so that results can be traced back
+func validateRules(rules []Rule) error {
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule name %s is used more than once", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return nil
+}
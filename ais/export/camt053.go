@@ -0,0 +1,451 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// Camt053Namespace is the XML namespace of ISO 20022 camt.053.001.02 bank to customer statements
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// Statement is an account statement for a period, used by WriteCamt053
+type Statement struct {
+	// ID identifies the statement, defaults to the account id followed by the last date of the statement
+	ID string
+	// MessageID identifies the message, defaults to the statement id
+	MessageID string
+	// SequenceNumber is the electronic sequence number of the statement, omitted when 0
+	SequenceNumber int
+	// CreatedAt defaults to the current time
+	CreatedAt time.Time
+	Account   ais.AccountDetailed
+	// From and To are the first and last dates of the statement, they default to the first and last booking date of
+	// the transactions
+	From, To       nordeago.Date
+	OpeningBalance nordeago.Money
+	// ClosingBalance defaults to the opening balance plus the booked transactions
+	ClosingBalance nordeago.Money
+	Transactions   []ais.Transaction
+}
+
+// WriteCamt053 writes the statement as a camt.053.001.02 XML document. Booked transactions are written with status
+// BOOK and pending transactions with status PDNG, only booked transactions count towards the closing balance.
+func WriteCamt053(w io.Writer, statement Statement) error {
+	document, err := newCamt053Document(statement)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+type camtDocument struct {
+	XMLName xml.Name      `xml:"Document"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Stmt    camtBkToCstmr `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstmr struct {
+	GrpHdr camtGroupHeader `xml:"GrpHdr"`
+	Stmt   camtStatement   `xml:"Stmt"`
+}
+
+type camtGroupHeader struct {
+	MsgID   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	ID         string        `xml:"Id"`
+	ElctrncSeq int           `xml:"ElctrncSeqNb,omitempty"`
+	CreDtTm    string        `xml:"CreDtTm"`
+	FrToDt     camtFromTo    `xml:"FrToDt"`
+	Acct       camtAccount   `xml:"Acct"`
+	Bal        []camtBalance `xml:"Bal"`
+	TxsSummry  camtSummary   `xml:"TxsSummry"`
+	Ntry       []camtEntry   `xml:"Ntry"`
+}
+
+type camtFromTo struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	ID   camtAccountID   `xml:"Id"`
+	Ccy  string          `xml:"Ccy,omitempty"`
+	Nm   string          `xml:"Nm,omitempty"`
+	Ownr *camtAccountOwn `xml:"Ownr,omitempty"`
+	Svcr *camtServicer   `xml:"Svcr,omitempty"`
+}
+
+type camtAccountID struct {
+	IBAN string `xml:"IBAN,omitempty"`
+	Othr *struct {
+		ID string `xml:"Id"`
+	} `xml:"Othr,omitempty"`
+}
+
+type camtAccountOwn struct {
+	Nm string `xml:"Nm"`
+}
+
+type camtServicer struct {
+	FinInstnID struct {
+		BIC string `xml:"BIC,omitempty"`
+		Nm  string `xml:"Nm,omitempty"`
+	} `xml:"FinInstnId"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Dt string `xml:"Dt"`
+}
+
+type camtBalance struct {
+	Tp struct {
+		CdOrPrtry struct {
+			Cd string `xml:"Cd"`
+		} `xml:"CdOrPrtry"`
+	} `xml:"Tp"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Dt        camtDate   `xml:"Dt"`
+}
+
+type camtSummary struct {
+	TtlNtries struct {
+		NbOfNtries    int    `xml:"NbOfNtries"`
+		Sum           string `xml:"Sum"`
+		TtlNetNtryAmt string `xml:"TtlNetNtryAmt"`
+		CdtDbtInd     string `xml:"CdtDbtInd"`
+	} `xml:"TtlNtries"`
+	TtlCdtNtries camtSummaryTotal `xml:"TtlCdtNtries"`
+	TtlDbtNtries camtSummaryTotal `xml:"TtlDbtNtries"`
+}
+
+type camtSummaryTotal struct {
+	NbOfNtries int    `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	Amt          camtAmount     `xml:"Amt"`
+	CdtDbtInd    string         `xml:"CdtDbtInd"`
+	Sts          string         `xml:"Sts"`
+	BookgDt      *camtDate      `xml:"BookgDt,omitempty"`
+	ValDt        *camtDate      `xml:"ValDt,omitempty"`
+	AcctSvcrRef  string         `xml:"AcctSvcrRef,omitempty"`
+	BkTxCd       camtBankTxCode `xml:"BkTxCd"`
+	NtryDtls     camtEntryDtls  `xml:"NtryDtls"`
+	AddtlNtryInf string         `xml:"AddtlNtryInf,omitempty"`
+}
+
+type camtBankTxCode struct {
+	Prtry struct {
+		Cd string `xml:"Cd"`
+	} `xml:"Prtry"`
+}
+
+type camtEntryDtls struct {
+	TxDtls camtTxDetails `xml:"TxDtls"`
+}
+
+type camtTxDetails struct {
+	Refs *struct {
+		AcctSvcrRef string `xml:"AcctSvcrRef"`
+	} `xml:"Refs,omitempty"`
+	AmtDtls   *camtAmountDetails `xml:"AmtDtls,omitempty"`
+	RltdPties *camtParties       `xml:"RltdPties,omitempty"`
+	RmtInf    *camtRemittance    `xml:"RmtInf,omitempty"`
+}
+
+type camtAmountDetails struct {
+	InstdAmt struct {
+		Amt camtAmount `xml:"Amt"`
+	} `xml:"InstdAmt"`
+}
+
+type camtParties struct {
+	Dbtr *camtAccountOwn `xml:"Dbtr,omitempty"`
+	Cdtr *camtAccountOwn `xml:"Cdtr,omitempty"`
+}
+
+type camtRemittance struct {
+	Ustrd []string `xml:"Ustrd,omitempty"`
+	Strd  *struct {
+		CdtrRefInf struct {
+			Ref string `xml:"Ref"`
+		} `xml:"CdtrRefInf"`
+	} `xml:"Strd,omitempty"`
+}
+
+func newCamt053Document(s Statement) (camtDocument, error) {
+	currency := s.Account.Currency
+	if len(currency) == 0 {
+		currency = s.OpeningBalance.Currency
+	}
+	if len(currency) != 3 {
+		return camtDocument{}, fmt.Errorf("statement currency %q must be an ISO 4217 currency code", currency)
+	}
+
+	from, to := s.From, s.To
+	closing := nordeago.NewMoney(s.OpeningBalance.Amount, currency)
+	if !closing.Amount.IsSet() {
+		closing.Amount = nordeago.NewDecimal(0, 0)
+	}
+	opening := closing
+
+	var credits, debits camtSummaryTotal
+	creditSum, debitSum := nordeago.NewDecimal(0, 0), nordeago.NewDecimal(0, 0)
+	entries := make([]camtEntry, 0, len(s.Transactions))
+	for _, t := range s.Transactions {
+		data := t.Data()
+		if !strings.EqualFold(data.Currency, currency) {
+			return camtDocument{}, fmt.Errorf("transaction %s has currency %s, the statement currency is %s", data.TransactionID, data.Currency, currency)
+		}
+
+		entry := newCamtEntry(t)
+		entries = append(entries, entry)
+
+		if data.Status.IsPending() {
+			continue
+		}
+		amount := t.SignedAmount()
+		closing.Amount = closing.Amount.Add(amount.Amount)
+		if amount.Sign() < 0 {
+			debits.NbOfNtries++
+			debitSum = debitSum.Add(amount.Amount.Abs())
+		} else {
+			credits.NbOfNtries++
+			creditSum = creditSum.Add(amount.Amount)
+		}
+
+		if date := data.BookingDate; !date.IsZero() {
+			if s.From.IsZero() && (from.IsZero() || date.Before(from)) {
+				from = date
+			}
+			if s.To.IsZero() && (to.IsZero() || date.After(to)) {
+				to = date
+			}
+		}
+	}
+	if s.ClosingBalance.Amount.IsSet() {
+		closing = nordeago.NewMoney(s.ClosingBalance.Amount, currency)
+	}
+	if from.IsZero() || to.IsZero() {
+		return camtDocument{}, fmt.Errorf("statement needs a from and to date when there are no booked transactions")
+	}
+
+	createdAt := s.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	id := s.ID
+	if len(id) == 0 {
+		id = truncate(fmt.Sprintf("%s-%s", accountNumber(s.Account), formatDate(to, "20060102")), 35)
+	}
+	messageID := s.MessageID
+	if len(messageID) == 0 {
+		messageID = id
+	}
+
+	credits.Sum = camtDecimal(creditSum, currency)
+	debits.Sum = camtDecimal(debitSum, currency)
+
+	statement := camtStatement{
+		ID:         id,
+		ElctrncSeq: s.SequenceNumber,
+		CreDtTm:    createdAt.Format("2006-01-02T15:04:05.000Z07:00"),
+		FrToDt: camtFromTo{
+			FrDtTm: formatDate(from, "2006-01-02") + "T00:00:00",
+			ToDtTm: formatDate(to, "2006-01-02") + "T23:59:59",
+		},
+		Acct: newCamtAccount(s.Account, currency),
+		Bal: []camtBalance{
+			newCamtBalance("OPBD", opening, from),
+			newCamtBalance("CLBD", closing, to),
+		},
+		Ntry: entries,
+	}
+	statement.TxsSummry.TtlNtries.NbOfNtries = credits.NbOfNtries + debits.NbOfNtries
+	statement.TxsSummry.TtlNtries.Sum = camtDecimal(creditSum.Add(debitSum), currency)
+	net := creditSum.Sub(debitSum)
+	statement.TxsSummry.TtlNtries.TtlNetNtryAmt = camtDecimal(net.Abs(), currency)
+	statement.TxsSummry.TtlNtries.CdtDbtInd = creditDebitIndicator(net.Sign())
+	statement.TxsSummry.TtlCdtNtries = credits
+	statement.TxsSummry.TtlDbtNtries = debits
+
+	return camtDocument{
+		Xmlns: Camt053Namespace,
+		Stmt: camtBkToCstmr{
+			GrpHdr: camtGroupHeader{MsgID: truncate(messageID, 35), CreDtTm: statement.CreDtTm},
+			Stmt:   statement,
+		},
+	}, nil
+}
+
+func newCamtAccount(account ais.AccountDetailed, currency string) camtAccount {
+	result := camtAccount{Ccy: currency, Nm: truncate(account.AccountName, 70)}
+
+	number := accountNumber(account)
+	if isIBAN(account, number) {
+		result.ID.IBAN = number
+	} else {
+		result.ID.Othr = &struct {
+			ID string `xml:"Id"`
+		}{truncate(number, 34)}
+	}
+	if len(account.OwnerName) > 0 {
+		result.Ownr = &camtAccountOwn{Nm: truncate(account.OwnerName, 140)}
+	}
+	if len(account.Bank.BIC) > 0 || len(account.Bank.Name) > 0 {
+		result.Svcr = &camtServicer{}
+		result.Svcr.FinInstnID.BIC = account.Bank.BIC
+		if len(account.Bank.BIC) == 0 {
+			result.Svcr.FinInstnID.Nm = truncate(account.Bank.Name, 140)
+		}
+	}
+	return result
+}
+
+func newCamtBalance(code string, balance nordeago.Money, date nordeago.Date) camtBalance {
+	var result camtBalance
+	result.Tp.CdOrPrtry.Cd = code
+	result.Amt = camtAmount{Ccy: balance.Currency, Value: camtDecimal(balance.Amount.Abs(), balance.Currency)}
+	result.CdtDbtInd = creditDebitIndicator(balance.Sign())
+	result.Dt.Dt = formatDate(date, "2006-01-02")
+	return result
+}
+
+func newCamtEntry(t ais.Transaction) camtEntry {
+	data := t.Data()
+	amount := t.SignedAmount()
+
+	entry := camtEntry{
+		Amt:          camtAmount{Ccy: amount.Currency, Value: camtDecimal(amount.Amount.Abs(), amount.Currency)},
+		CdtDbtInd:    creditDebitIndicator(amount.Sign()),
+		Sts:          "BOOK",
+		AcctSvcrRef:  truncate(data.TransactionID, 35),
+		AddtlNtryInf: truncate(data.Narrative, 500),
+	}
+	if data.Status.IsPending() {
+		entry.Sts = "PDNG"
+	}
+	if !data.BookingDate.IsZero() {
+		entry.BookgDt = &camtDate{Dt: formatDate(data.BookingDate, "2006-01-02")}
+	}
+	if !data.ValueDate.IsZero() {
+		entry.ValDt = &camtDate{Dt: formatDate(data.ValueDate, "2006-01-02")}
+	}
+	entry.BkTxCd.Prtry.Cd = truncate(data.TypeDescription, 35)
+	if len(entry.BkTxCd.Prtry.Cd) == 0 {
+		entry.BkTxCd.Prtry.Cd = t.TransactionType()
+	}
+
+	details := &entry.NtryDtls.TxDtls
+	if len(data.TransactionID) > 0 {
+		details.Refs = &struct {
+			AcctSvcrRef string `xml:"AcctSvcrRef"`
+		}{truncate(data.TransactionID, 35)}
+	}
+	if original := data.OriginalAmount(); original.Amount.IsSet() && len(original.Currency) == 3 {
+		details.AmtDtls = &camtAmountDetails{}
+		details.AmtDtls.InstdAmt.Amt = camtAmount{Ccy: original.Currency, Value: camtDecimal(original.Amount.Abs(), original.Currency)}
+	}
+	if len(data.CounterpartyName) > 0 {
+		party := &camtAccountOwn{Nm: truncate(data.CounterpartyName, 140)}
+		// The counterparty of an incoming payment is the debtor and of an outgoing payment the creditor
+		if amount.Sign() < 0 {
+			details.RltdPties = &camtParties{Cdtr: party}
+		} else {
+			details.RltdPties = &camtParties{Dbtr: party}
+		}
+	}
+	if len(data.Message) > 0 || len(data.Reference) > 0 {
+		details.RmtInf = &camtRemittance{}
+		if len(data.Message) > 0 {
+			details.RmtInf.Ustrd = []string{truncate(data.Message, 140)}
+		}
+		if len(data.Reference) > 0 {
+			details.RmtInf.Strd = &struct {
+				CdtrRefInf struct {
+					Ref string `xml:"Ref"`
+				} `xml:"CdtrRefInf"`
+			}{}
+			details.RmtInf.Strd.CdtrRefInf.Ref = truncate(data.Reference, 35)
+		}
+	}
+	return entry
+}
+
+// camtDecimal formats the amount with the minor units of the currency
+func camtDecimal(amount nordeago.Decimal, currency string) string {
+	return nordeago.NewMoney(amount, currency).Round().Amount.String()
+}
+
+func creditDebitIndicator(sign int) string {
+	if sign < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func isIBAN(account ais.AccountDetailed, number string) bool {
+	for _, n := range append([]ais.AccountNumber{account.AccountNumber}, account.AccountNumbers...) {
+		if n.Value == number {
+			return strings.EqualFold(n.Type, "IBAN")
+		}
+	}
+	return false
+}
+
+// truncate shortens s to at most n characters, ISO 20022 text fields have a maximum length
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
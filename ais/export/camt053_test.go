@@ -0,0 +1,258 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// camt053Sequences contains the child elements of the camt.053.001.02 schema types used by WriteCamt053 in schema
+// order, required elements end with !
+var camt053Sequences = map[string][]string{
+	"Document":                                                       {"BkToCstmrStmt!"},
+	"BkToCstmrStmt":                                                  {"GrpHdr!", "Stmt!"},
+	"BkToCstmrStmt/GrpHdr":                                           {"MsgId!", "CreDtTm!", "MsgRcpt", "MsgPgntn", "AddtlInf"},
+	"BkToCstmrStmt/Stmt":                                             {"Id!", "ElctrncSeqNb", "LglSeqNb", "CreDtTm!", "FrToDt", "CpyDplctInd", "RptgSrc", "Acct!", "RltdAcct", "Intrst", "Bal!", "TxsSummry", "Ntry", "AddtlStmtInf"},
+	"BkToCstmrStmt/Stmt/FrToDt":                                      {"FrDtTm!", "ToDtTm!"},
+	"BkToCstmrStmt/Stmt/Acct":                                        {"Id!", "Tp", "Ccy", "Nm", "Ownr", "Svcr"},
+	"BkToCstmrStmt/Stmt/Acct/Id":                                     {"IBAN", "Othr"},
+	"BkToCstmrStmt/Stmt/Acct/Ownr":                                   {"Nm", "PstlAdr", "Id", "CtryOfRes", "CtctDtls"},
+	"BkToCstmrStmt/Stmt/Acct/Svcr":                                   {"FinInstnId!", "BrnchId"},
+	"BkToCstmrStmt/Stmt/Acct/Svcr/FinInstnId":                        {"BIC", "ClrSysMmbId", "Nm", "PstlAdr", "Othr"},
+	"BkToCstmrStmt/Stmt/Bal":                                         {"Tp!", "CdtLine", "Amt!", "CdtDbtInd!", "Dt!", "Avlbty"},
+	"BkToCstmrStmt/Stmt/Bal/Tp":                                      {"CdOrPrtry!", "SubTp"},
+	"BkToCstmrStmt/Stmt/Bal/Tp/CdOrPrtry":                            {"Cd", "Prtry"},
+	"BkToCstmrStmt/Stmt/Bal/Dt":                                      {"Dt", "DtTm"},
+	"BkToCstmrStmt/Stmt/TxsSummry":                                   {"TtlNtries", "TtlCdtNtries", "TtlDbtNtries", "TtlNtriesPerBkTxCd"},
+	"BkToCstmrStmt/Stmt/TxsSummry/TtlNtries":                         {"NbOfNtries", "Sum", "TtlNetNtryAmt", "CdtDbtInd"},
+	"BkToCstmrStmt/Stmt/TxsSummry/TtlCdtNtries":                      {"NbOfNtries", "Sum"},
+	"BkToCstmrStmt/Stmt/TxsSummry/TtlDbtNtries":                      {"NbOfNtries", "Sum"},
+	"BkToCstmrStmt/Stmt/Ntry":                                        {"NtryRef", "Amt!", "CdtDbtInd!", "RvslInd", "Sts!", "BookgDt", "ValDt", "AcctSvcrRef", "Avlbty", "BkTxCd!", "ComssnWvrInd", "AddtlInfInd", "AmtDtls", "Chrgs", "TechInptChanl", "Intrst", "NtryDtls", "AddtlNtryInf"},
+	"BkToCstmrStmt/Stmt/Ntry/BookgDt":                                {"Dt", "DtTm"},
+	"BkToCstmrStmt/Stmt/Ntry/ValDt":                                  {"Dt", "DtTm"},
+	"BkToCstmrStmt/Stmt/Ntry/BkTxCd":                                 {"Domn", "Prtry"},
+	"BkToCstmrStmt/Stmt/Ntry/BkTxCd/Prtry":                           {"Cd!", "Issr"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls":                               {"Btch", "TxDtls"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls":                        {"Refs", "AmtDtls", "Avlbty", "BkTxCd", "Chrgs", "Intrst", "RltdPties", "RltdAgts", "Purp", "RltdRmtInf", "RmtInf", "RltdDts", "RltdPric", "RltdQties", "FinInstrmId", "Tax", "RtrInf", "CorpActn", "SfkpgAcct", "AddtlTxInf"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/Refs":                   {"MsgId", "AcctSvcrRef", "PmtInfId", "InstrId", "EndToEndId", "TxId", "MndtId", "ChqNb", "ClrSysRef", "Prtry"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/AmtDtls":                {"InstdAmt", "TxAmt", "CntrValAmt", "AnncdPstngAmt", "PrtryAmt"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/AmtDtls/InstdAmt":       {"Amt!", "CcyXchg"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/RltdPties":              {"InitgPty", "Dbtr", "DbtrAcct", "UltmtDbtr", "Cdtr", "CdtrAcct", "UltmtCdtr", "TradgPty", "Prtry"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/RltdPties/Dbtr":         {"Nm", "PstlAdr", "Id", "CtryOfRes", "CtctDtls"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/RltdPties/Cdtr":         {"Nm", "PstlAdr", "Id", "CtryOfRes", "CtctDtls"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/RmtInf":                 {"Ustrd", "Strd"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/RmtInf/Strd":            {"RfrdDocInf", "RfrdDocAmt", "CdtrRefInf", "Invcr", "Invcee", "AddtlRmtInf"},
+	"BkToCstmrStmt/Stmt/Ntry/NtryDtls/TxDtls/RmtInf/Strd/CdtrRefInf": {"Tp", "Ref"},
+}
+
+// camt053Patterns contains the restrictions of the simple types used by WriteCamt053
+var camt053Patterns = map[string]*regexp.Regexp{
+	"Amt":        regexp.MustCompile(`^[0-9]{1,13}(\.[0-9]{1,5})?$`),
+	"Sum":        regexp.MustCompile(`^[0-9]{1,13}(\.[0-9]{1,5})?$`),
+	"CdtDbtInd":  regexp.MustCompile(`^(CRDT|DBIT)$`),
+	"Sts":        regexp.MustCompile(`^(BOOK|PDNG|INFO)$`),
+	"Dt":         regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`),
+	"IBAN":       regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[a-zA-Z0-9]{1,30}$`),
+	"BIC":        regexp.MustCompile(`^[A-Z]{6}[A-Z2-9][A-NP-Z0-9]([A-Z0-9]{3})?$`),
+	"Ccy":        regexp.MustCompile(`^[A-Z]{3}$`),
+	"NbOfNtries": regexp.MustCompile(`^[0-9]{1,15}$`),
+}
+
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+func parseXML(t *testing.T, data []byte) *xmlNode {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name.Local, attrs: token.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += strings.TrimSpace(string(token))
+			}
+		}
+	}
+	if root == nil {
+		t.Fatal("document has no root element")
+	}
+	return root
+}
+
+// checkSchemaShape checks the order and presence of the child elements and the restricted values of the node
+func checkSchemaShape(t *testing.T, node *xmlNode, path string) {
+	if pattern, ok := camt053Patterns[node.name]; ok && len(node.children) == 0 && !pattern.MatchString(node.text) {
+		t.Errorf("%s was incorrect, got: %q, want: a value matching %s.", path, node.text, pattern)
+	}
+
+	sequence, ok := camt053Sequences[path]
+	if !ok {
+		if len(node.children) > 0 {
+			t.Errorf("%s has child elements that are not in the schema shape", path)
+		}
+		return
+	}
+
+	position := 0
+	found := make(map[string]bool)
+	for _, child := range node.children {
+		for position < len(sequence) && strings.TrimSuffix(sequence[position], "!") != child.name {
+			position++
+		}
+		if position == len(sequence) {
+			t.Errorf("%s/%s is not allowed at this position", path, child.name)
+			return
+		}
+		found[child.name] = true
+		childPath := path + "/" + child.name
+		if path == "Document" {
+			childPath = child.name
+		}
+		checkSchemaShape(t, child, childPath)
+	}
+	for _, element := range sequence {
+		if strings.HasSuffix(element, "!") && !found[strings.TrimSuffix(element, "!")] {
+			t.Errorf("%s is missing the required element %s", path, strings.TrimSuffix(element, "!"))
+		}
+	}
+}
+
+func findNodes(node *xmlNode, path ...string) []*xmlNode {
+	if len(path) == 0 {
+		return []*xmlNode{node}
+	}
+	var result []*xmlNode
+	for _, child := range node.children {
+		if child.name == path[0] {
+			result = append(result, findNodes(child, path[1:]...)...)
+		}
+	}
+	return result
+}
+
+func TestWriteCamt053(t *testing.T) {
+	transactions := testTransactions()
+	pending := *transactions[1].Data()
+	pending.TransactionID = "3"
+	pending.Status = "pending"
+	pending.BookingDate = nordeago.Date{}
+	foreign := *transactions[1].Data()
+	foreign.TransactionID = "4"
+	foreign.OriginalCurrency = "EUR"
+	foreign.OriginalCurrencyAmount = nordeago.MustParseDecimal("5.123")
+	transactions = append(transactions, &ais.DebitTransaction{TransactionData: pending}, &ais.DebitTransaction{TransactionData: foreign})
+
+	var b bytes.Buffer
+	err := WriteCamt053(&b, Statement{
+		CreatedAt:      time.Date(2018, 10, 4, 8, 0, 0, 0, time.UTC),
+		Account:        testAccount(),
+		OpeningBalance: nordeago.NewMoney(nordeago.MustParseDecimal("-100"), "SEK"),
+		Transactions:   transactions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := parseXML(t, b.Bytes())
+	if root.name != "Document" || len(root.attrs) == 0 || root.attrs[0].Value != Camt053Namespace {
+		t.Fatalf("root was incorrect, got: %s %v, want: Document in namespace %s.", root.name, root.attrs, Camt053Namespace)
+	}
+	checkSchemaShape(t, root, "Document")
+
+	statement := findNodes(root, "BkToCstmrStmt", "Stmt")[0]
+	if id := findNodes(statement, "Id")[0].text; id != "SE7030000000041770042136-20181002" {
+		t.Errorf("Id was incorrect, got: %s, want: %s.", id, "SE7030000000041770042136-20181002")
+	}
+	if iban := findNodes(statement, "Acct", "Id", "IBAN")[0].text; iban != "SE7030000000041770042136" {
+		t.Errorf("IBAN was incorrect, got: %s, want: %s.", iban, "SE7030000000041770042136")
+	}
+
+	balances := findNodes(statement, "Bal")
+	opening, closing := balances[0], balances[1]
+	if findNodes(opening, "Amt")[0].text != "100.00" || findNodes(opening, "CdtDbtInd")[0].text != "DBIT" || findNodes(opening, "Dt", "Dt")[0].text != "2018-10-01" {
+		t.Errorf("opening balance was incorrect, got: %+v, want: 100.00 DBIT on 2018-10-01.", opening)
+	}
+	// -100 + 25000 - 59.90 - 59.90, the pending transaction is not booked
+	if findNodes(closing, "Amt")[0].text != "24780.20" || findNodes(closing, "CdtDbtInd")[0].text != "CRDT" || findNodes(closing, "Dt", "Dt")[0].text != "2018-10-02" {
+		t.Errorf("closing balance was incorrect, got: %+v, want: 24780.20 CRDT on 2018-10-02.", closing)
+	}
+	if n := findNodes(statement, "TxsSummry", "TtlDbtNtries", "NbOfNtries")[0].text; n != "2" {
+		t.Errorf("number of debit entries was incorrect, got: %s, want: 2.", n)
+	}
+
+	entries := findNodes(statement, "Ntry")
+	if len(entries) != 4 {
+		t.Fatalf("entries were incorrect, got: %d, want: 4.", len(entries))
+	}
+	if findNodes(entries[0], "CdtDbtInd")[0].text != "CRDT" || findNodes(entries[0], "NtryDtls", "TxDtls", "RltdPties", "Dbtr", "Nm")[0].text != "Acme AB" {
+		t.Errorf("credit entry was incorrect, got: %+v, want: CRDT with debtor Acme AB.", entries[0])
+	}
+	if ref := findNodes(entries[0], "NtryDtls", "TxDtls", "RmtInf", "Strd", "CdtrRefInf", "Ref")[0].text; ref != "4711" {
+		t.Errorf("reference was incorrect, got: %s, want: 4711.", ref)
+	}
+	if findNodes(entries[1], "CdtDbtInd")[0].text != "DBIT" || len(findNodes(entries[1], "NtryDtls", "TxDtls", "RltdPties", "Cdtr")) != 1 {
+		t.Errorf("debit entry was incorrect, got: %+v, want: DBIT with a creditor.", entries[1])
+	}
+	if sts := findNodes(entries[2], "Sts")[0].text; sts != "PDNG" {
+		t.Errorf("status was incorrect, got: %s, want: PDNG.", sts)
+	}
+	if amount := findNodes(entries[3], "NtryDtls", "TxDtls", "AmtDtls", "InstdAmt", "Amt")[0]; amount.text != "5.12" || amount.attrs[0].Value != "EUR" {
+		t.Errorf("instructed amount was incorrect, got: %s %v, want: 5.12 EUR.", amount.text, amount.attrs)
+	}
+}
+
+func TestWriteCamt053CurrencyMismatch(t *testing.T) {
+	transactions := testTransactions()
+	transactions[0].Data().Currency = "EUR"
+	err := WriteCamt053(&bytes.Buffer{}, Statement{Account: testAccount(), Transactions: transactions})
+	if err == nil {
+		t.Errorf("WriteCamt053 was incorrect, got: nil, want: currency mismatch error.")
+	}
+}
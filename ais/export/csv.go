@@ -0,0 +1,351 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package export writes account transactions in file formats used by spreadsheets, accounting systems and personal
+// finance software
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// Column is a transaction field written as a CSV column
+type Column string
+
+// Columns that can be written by a CSVWriter
+const (
+	ColumnBookingDate      Column = "bookingDate"
+	ColumnValueDate        Column = "valueDate"
+	ColumnTransactionDate  Column = "transactionDate"
+	ColumnTransactionID    Column = "transactionId"
+	ColumnType             Column = "type"
+	ColumnStatus           Column = "status"
+	ColumnAmount           Column = "amount" // Negative for debit transactions
+	ColumnCurrency         Column = "currency"
+	ColumnCounterparty     Column = "counterparty"
+	ColumnMessage          Column = "message"
+	ColumnNarrative        Column = "narrative"
+	ColumnReference        Column = "reference"
+	ColumnBalanceAfter     Column = "balanceAfterTransaction"
+	ColumnOriginalAmount   Column = "originalAmount"
+	ColumnOriginalCurrency Column = "originalCurrency"
+	ColumnCardNumber       Column = "cardNumber"
+	ColumnTypeDescription  Column = "typeDescription"
+	// ColumnMerchant is the normalized counterparty, see merchant.Normalizer
+	ColumnMerchant Column = "merchant"
+)
+
+// DefaultColumns are written when CSVOptions.Columns is empty
+var DefaultColumns = []Column{
+	ColumnBookingDate,
+	ColumnValueDate,
+	ColumnAmount,
+	ColumnCurrency,
+	ColumnCounterparty,
+	ColumnNarrative,
+	ColumnMessage,
+	ColumnReference,
+	ColumnBalanceAfter,
+	ColumnTransactionID,
+}
+
+// CSVOptions configures the output of a CSVWriter, the zero value writes the default columns as comma separated
+// values with ISO dates
+type CSVOptions struct {
+	Columns []Column
+	// Headers replaces the column names in the header row
+	Headers map[Column]string
+	// Delimiter separates the fields, defaults to ','
+	Delimiter rune
+	// DecimalComma writes amounts with a decimal comma instead of a decimal point
+	DecimalComma bool
+	// DateFormat is the layout used for dates, defaults to 2006-01-02
+	DateFormat string
+	// BOM starts the file with a UTF-8 byte order mark so that Excel detects the encoding
+	BOM bool
+	// NoHeader skips the row with the column names
+	NoHeader bool
+	// NoFormulaEscape writes text that starts with =, +, -, @, a tab or a carriage return as is. By default such text
+	// is prefixed with ' so that spreadsheets do not evaluate narratives chosen by a counterparty as formulas.
+	NoFormulaEscape bool
+}
+
+// SwedishExcelOptions returns options that Excel with Swedish regional settings opens without an import dialog
+func SwedishExcelOptions() CSVOptions {
+	return CSVOptions{
+		Delimiter:    ';',
+		DecimalComma: true,
+		BOM:          true,
+	}
+}
+
+// CSVWriter writes transactions as CSV rows. The account metadata and the header row are written before the first
+// transaction.
+type CSVWriter struct {
+	w       io.Writer
+	csv     *csv.Writer
+	options CSVOptions
+	started bool
+	width   int // Rows are padded to this number of fields after the account has been written
+
+	bomWritten bool
+}
+
+// NewCSVWriter returns a CSVWriter writing to w
+func NewCSVWriter(w io.Writer, options CSVOptions) *CSVWriter {
+	if len(options.Columns) == 0 {
+		options.Columns = DefaultColumns
+	}
+	if len(options.DateFormat) == 0 {
+		options.DateFormat = "2006-01-02"
+	}
+
+	writer := csv.NewWriter(w)
+	if options.Delimiter != 0 {
+		writer.Comma = options.Delimiter
+	}
+	return &CSVWriter{w: w, csv: writer, options: options}
+}
+
+// WriteAccount writes the account metadata as label and value rows followed by an empty row. The rows are padded to
+// the number of columns so that every row has the same number of fields. It must be called before any transaction is
+// written.
+func (w *CSVWriter) WriteAccount(account ais.AccountDetailed) error {
+	if w.started {
+		return fmt.Errorf("account must be written before the transactions")
+	}
+	if err := w.start(false); err != nil {
+		return err
+	}
+
+	rows := [][]string{
+		{"Account", w.text(account.AccountName)},
+		{"Account number", accountNumber(account)},
+		{"Currency", account.Currency},
+		{"Owner", w.text(account.OwnerName)},
+		{"Bank", w.text(account.Bank.Name)},
+		{"Booked balance", w.amount(account.BookedBalance)},
+		{"Available balance", w.amount(account.AvailableBalance)},
+	}
+	w.width = max(len(w.options.Columns), 2)
+	for _, row := range rows {
+		if len(row[1]) == 0 {
+			continue
+		}
+		if err := w.csv.Write(padRow(row, w.width)); err != nil {
+			return err
+		}
+	}
+	return w.csv.Write(make([]string, w.width))
+}
+
+// Write writes a transaction as a row
+func (w *CSVWriter) Write(t ais.Transaction) error {
+	if err := w.start(true); err != nil {
+		return err
+	}
+
+	row := make([]string, len(w.options.Columns))
+	for i, column := range w.options.Columns {
+		value, err := w.value(t, column)
+		if err != nil {
+			return err
+		}
+		row[i] = value
+	}
+	return w.csv.Write(padRow(row, w.width))
+}
+
+// WriteAll writes the transactions and flushes the writer
+func (w *CSVWriter) WriteAll(transactions []ais.Transaction) error {
+	for _, t := range transactions {
+		if err := w.Write(t); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// WriteSeq writes the transactions returned by an iterator such as ais.Transactions and flushes the writer. The first
+// error returned by the iterator stops the export.
+func (w *CSVWriter) WriteSeq(transactions iter.Seq2[ais.Transaction, error]) error {
+	for t, err := range transactions {
+		if err != nil {
+			return err
+		}
+		if err := w.Write(t); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes buffered rows, the header is written even if there are no transactions
+func (w *CSVWriter) Flush() error {
+	if err := w.start(true); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// WriteCSV writes the transactions to w, the account metadata is written first when account is not nil
+func WriteCSV(w io.Writer, account *ais.AccountDetailed, transactions []ais.Transaction, options CSVOptions) error {
+	writer := NewCSVWriter(w, options)
+	if account != nil {
+		if err := writer.WriteAccount(*account); err != nil {
+			return err
+		}
+	}
+	return writer.WriteAll(transactions)
+}
+
+// start writes the byte order mark and, when header is true, the header row if they have not been written yet
+func (w *CSVWriter) start(header bool) error {
+	if w.options.BOM && !w.bomWritten {
+		w.bomWritten = true
+		if _, err := io.WriteString(w.w, "\ufeff"); err != nil {
+			return err
+		}
+	}
+	if !header || w.started {
+		return nil
+	}
+	w.started = true
+	if w.options.NoHeader {
+		return nil
+	}
+
+	row := make([]string, len(w.options.Columns))
+	for i, column := range w.options.Columns {
+		row[i] = string(column)
+		if header, ok := w.options.Headers[column]; ok {
+			row[i] = header
+		}
+	}
+	return w.csv.Write(padRow(row, w.width))
+}
+
+func (w *CSVWriter) value(t ais.Transaction, column Column) (string, error) {
+	data := t.Data()
+	switch column {
+	case ColumnBookingDate:
+		return w.date(data.BookingDate), nil
+	case ColumnValueDate:
+		return w.date(data.ValueDate), nil
+	case ColumnTransactionDate:
+		return w.date(data.TransactionDate), nil
+	case ColumnTransactionID:
+		return w.text(data.TransactionID), nil
+	case ColumnType:
+		return t.TransactionType(), nil
+	case ColumnStatus:
+		return string(data.Status), nil
+	case ColumnAmount:
+		return w.amount(t.SignedAmount().Amount), nil
+	case ColumnCurrency:
+		return data.Currency, nil
+	case ColumnCounterparty:
+		return w.text(data.CounterpartyName), nil
+	case ColumnMessage:
+		return w.text(data.Message), nil
+	case ColumnNarrative:
+		return w.text(data.Narrative), nil
+	case ColumnReference:
+		return w.text(data.Reference), nil
+	case ColumnBalanceAfter:
+		return w.amount(data.BalanceAfterTransaction), nil
+	case ColumnOriginalAmount:
+		return w.amount(data.OriginalCurrencyAmount), nil
+	case ColumnOriginalCurrency:
+		return data.OriginalCurrency, nil
+	case ColumnCardNumber:
+		return data.CardNumber, nil
+	case ColumnTypeDescription:
+		return w.text(data.TypeDescription), nil
+	case ColumnMerchant:
+		return w.text(data.NormalizedCounterparty), nil
+	}
+	return "", fmt.Errorf("unknown column %q", column)
+}
+
+func (w *CSVWriter) amount(d nordeago.Decimal) string {
+	if !d.IsSet() {
+		return ""
+	}
+	if w.options.DecimalComma {
+		return strings.Replace(d.String(), ".", ",", 1)
+	}
+	return d.String()
+}
+
+// text prefixes text that a spreadsheet would evaluate as a formula with ', unless NoFormulaEscape is set
+func (w *CSVWriter) text(value string) string {
+	if w.options.NoFormulaEscape || len(value) == 0 || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	return "'" + value
+}
+
+func (w *CSVWriter) date(d nordeago.Date) string {
+	return formatDate(d, w.options.DateFormat)
+}
+
+// padRow appends empty fields to the row until it has width fields
+func padRow(row []string, width int) []string {
+	for len(row) < width {
+		row = append(row, "")
+	}
+	return row
+}
+
+// formatDate formats the date with a time layout, an unset date is an empty string
+func formatDate(d nordeago.Date, layout string) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.In(time.UTC).Format(layout)
+}
+
+// accountNumber returns the IBAN of the account if there is one, otherwise the first account number or the id
+func accountNumber(account ais.AccountDetailed) string {
+	numbers := append([]ais.AccountNumber{account.AccountNumber}, account.AccountNumbers...)
+	for _, number := range numbers {
+		if strings.EqualFold(number.Type, "IBAN") && len(number.Value) > 0 {
+			return number.Value
+		}
+	}
+	for _, number := range numbers {
+		if len(number.Value) > 0 {
+			return number.Value
+		}
+	}
+	return account.ID
+}
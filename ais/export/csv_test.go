@@ -0,0 +1,158 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func testAccount() ais.AccountDetailed {
+	return ais.AccountDetailed{
+		AccountName:      "Checking",
+		AccountNumber:    ais.AccountNumber{Type: "BBAN_SE", Value: "41770042136"},
+		AccountNumbers:   []ais.AccountNumber{{Type: "IBAN", Value: "SE7030000000041770042136"}},
+		ID:               "SE7030000000041770042136-SEK",
+		Bank:             ais.Bank{BIC: "NDEASESS", Country: "SE", Name: "Nordea"},
+		BookedBalance:    nordeago.MustParseDecimal("1000.00"),
+		AvailableBalance: nordeago.MustParseDecimal("950.00"),
+		Currency:         "SEK",
+		OwnerName:        "Anna Andersson",
+	}
+}
+
+func testTransactions() []ais.Transaction {
+	return []ais.Transaction{
+		&ais.CreditTransaction{TransactionData: ais.TransactionData{
+			TransactionID:    "1",
+			Status:           ais.TransactionStatusBooked,
+			BookingDate:      nordeago.MustParseDate("2018-10-01"),
+			ValueDate:        nordeago.MustParseDate("2018-10-01"),
+			Amount:           nordeago.MustParseDecimal("25000.00"),
+			Currency:         "SEK",
+			CounterpartyName: "Acme AB",
+			Narrative:        "Lön",
+			Reference:        "4711",
+		}},
+		&ais.DebitTransaction{TransactionData: ais.TransactionData{
+			TransactionID:    "2",
+			Status:           ais.TransactionStatusBooked,
+			BookingDate:      nordeago.MustParseDate("2018-10-02"),
+			ValueDate:        nordeago.MustParseDate("2018-10-03"),
+			Amount:           nordeago.MustParseDecimal("59.90"),
+			Currency:         "SEK",
+			CounterpartyName: "ICA Maxi; Stockholm",
+			Narrative:        "Kortköp",
+		}},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	options := CSVOptions{Columns: []Column{ColumnBookingDate, ColumnAmount, ColumnCounterparty}}
+	if err := WriteCSV(&b, nil, testTransactions(), options); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "bookingDate,amount,counterparty\n2018-10-01,25000.00,Acme AB\n2018-10-02,-59.90,ICA Maxi; Stockholm\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV was incorrect, got: %q, want: %q.", b.String(), want)
+	}
+}
+
+func TestWriteCSVSwedishExcel(t *testing.T) {
+	var b bytes.Buffer
+	options := SwedishExcelOptions()
+	options.Columns = []Column{ColumnBookingDate, ColumnAmount, ColumnCounterparty}
+	options.Headers = map[Column]string{ColumnBookingDate: "Datum", ColumnAmount: "Belopp", ColumnCounterparty: "Mottagare"}
+	options.DateFormat = "02/01/2006"
+	account := testAccount()
+	if err := WriteCSV(&b, &account, testTransactions(), options); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\ufeffAccount;Checking;\nAccount number;SE7030000000041770042136;\nCurrency;SEK;\nOwner;Anna Andersson;\nBank;Nordea;\n" +
+		"Booked balance;1000,00;\nAvailable balance;950,00;\n;;\n" +
+		"Datum;Belopp;Mottagare\n01/10/2018;25000,00;Acme AB\n02/10/2018;-59,90;\"ICA Maxi; Stockholm\"\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV was incorrect, got: %q, want: %q.", b.String(), want)
+	}
+}
+
+func TestCSVWriterUnknownColumn(t *testing.T) {
+	var b bytes.Buffer
+	writer := NewCSVWriter(&b, CSVOptions{Columns: []Column{"unknown"}})
+	if err := writer.Write(testTransactions()[0]); err == nil {
+		t.Errorf("Write was incorrect, got: nil, want: unknown column error.")
+	}
+}
+
+func TestWriteCSVFieldCount(t *testing.T) {
+	for _, columns := range [][]Column{{ColumnAmount}, {ColumnBookingDate, ColumnAmount}, DefaultColumns} {
+		var b bytes.Buffer
+		account := testAccount()
+		if err := WriteCSV(&b, &account, testTransactions(), CSVOptions{Columns: columns}); err != nil {
+			t.Fatal(err)
+		}
+
+		// A strict reader requires every row to have the same number of fields as the first row
+		records, err := csv.NewReader(&b).ReadAll()
+		if err != nil {
+			t.Errorf("WriteCSV was incorrect for %d columns, got: %v, want: rows with the same number of fields.", len(columns), err)
+			continue
+		}
+		if len(records) != 11 {
+			t.Errorf("WriteCSV was incorrect, got: %d rows, want: 11.", len(records))
+		}
+	}
+}
+
+func TestWriteCSVFormulaEscape(t *testing.T) {
+	transaction := testTransactions()[1]
+	transaction.Data().CounterpartyName = "=HYPERLINK(\"http://example.com\")"
+	transaction.Data().Narrative = "@SUM(A1:A2)"
+	transaction.Data().Message = "+46 8 123 456"
+	columns := []Column{ColumnAmount, ColumnCounterparty, ColumnNarrative, ColumnMessage}
+
+	var b bytes.Buffer
+	if err := WriteCSV(&b, nil, []ais.Transaction{transaction}, CSVOptions{Columns: columns, NoHeader: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := "-59.90,\"'=HYPERLINK(\"\"http://example.com\"\")\",'@SUM(A1:A2),'+46 8 123 456\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV was incorrect, got: %q, want: %q.", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WriteCSV(&b, nil, []ais.Transaction{transaction}, CSVOptions{Columns: columns, NoHeader: true, NoFormulaEscape: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "-59.90,\"=HYPERLINK") {
+		t.Errorf("WriteCSV was incorrect, got: %q, want: text written as is.", b.String())
+	}
+}
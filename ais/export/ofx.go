@@ -0,0 +1,390 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+	"github.com/markustenghamn/nordeago/iban"
+)
+
+// ofxHeader is the processing instruction that starts an OFX 2.2 document
+const ofxHeader = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`
+
+// OFX transaction types written by WriteOFX
+const (
+	OFXTypeCredit   = "CREDIT"
+	OFXTypeDebit    = "DEBIT"
+	OFXTypeATM      = "ATM"
+	OFXTypePOS      = "POS"
+	OFXTypeFee      = "FEE"
+	OFXTypeInterest = "INT"
+	OFXTypeTransfer = "XFER"
+)
+
+// ofxTypeKeywords are words in the type description or narrative of Nordic transactions that identify an OFX
+// transaction type, in the order they are checked. Keywords must match whole words, rules with debitOnly set are
+// only used for debits so that for example a refunded fee stays a credit.
+var ofxTypeKeywords = []struct {
+	trnType   string
+	debitOnly bool
+	keywords  []string
+}{
+	{OFXTypeATM, true, []string{"uttag", "kontantuttag", "bankomat", "atm", "käteisnosto", "automaatti", "hævning", "minibank"}},
+	{OFXTypeFee, true, []string{"avgift", "fee", "palvelumaksu", "gebyr"}},
+	{OFXTypeInterest, false, []string{"ränta", "interest", "korko", "rente", "renter"}},
+	{OFXTypePOS, true, []string{"kortköp", "korttiosto", "kortkøb", "varekjøp", "card purchase"}},
+	{OFXTypeTransfer, false, []string{"överföring", "transfer", "tilisiirto", "overførsel", "overføring"}},
+}
+
+// OFXTransactionType returns the OFX TRNTYPE of a transaction. Whether the transaction is a credit or a debit is
+// decided by its _type, or by the sign of the amount for unknown types. INT and XFER are used when the type
+// description or narrative identifies the transaction, debits can also be ATM, FEE or POS and card debits are POS.
+// Other transactions are CREDIT or DEBIT.
+func OFXTransactionType(t ais.Transaction) string {
+	data := t.Data()
+	debit := t.SignedAmount().Amount.Sign() < 0
+	switch t.TransactionType() {
+	case ais.TransactionTypeCredit:
+		debit = false
+	case ais.TransactionTypeDebit:
+		debit = true
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(data.TypeDescription+" "+data.Narrative), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	text := " " + strings.Join(words, " ") + " "
+	for _, rule := range ofxTypeKeywords {
+		if rule.debitOnly && !debit {
+			continue
+		}
+		for _, keyword := range rule.keywords {
+			if strings.Contains(text, " "+keyword+" ") {
+				return rule.trnType
+			}
+		}
+	}
+	if !debit {
+		return OFXTypeCredit
+	}
+	if len(data.CardNumber) > 0 {
+		return OFXTypePOS
+	}
+	return OFXTypeDebit
+}
+
+// WriteOFX writes the statement as an OFX 2.2 bank statement response. The ledger balance is the closing balance of
+// the statement or the booked balance of the account, the available balance of the account is included when it is
+// set. Pending transactions are left out since finance software does not update imported transactions. The FITID of
+// a transaction is its transaction id, or its fingerprint if it has none, see ais.Fingerprints.
+func WriteOFX(w io.Writer, statement Statement) error {
+	document, err := newOFXDocument(statement)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n"+ofxHeader+"\n"); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	Signon  struct {
+		Response struct {
+			Status   ofxStatus `xml:"STATUS"`
+			DTServer string    `xml:"DTSERVER"`
+			Language string    `xml:"LANGUAGE"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			TrnUID    string            `xml:"TRNUID"`
+			Status    ofxStatus         `xml:"STATUS"`
+			Statement ofxStatementReply `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxStatementReply struct {
+	CurDef      string `xml:"CURDEF"`
+	BankAccount struct {
+		BankID   string `xml:"BANKID"`
+		AcctID   string `xml:"ACCTID"`
+		AcctType string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	TransactionList struct {
+		DTStart      string           `xml:"DTSTART"`
+		DTEnd        string           `xml:"DTEND"`
+		Transactions []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBalance    ofxBalance  `xml:"LEDGERBAL"`
+	AvailableBalance *ofxBalance `xml:"AVAILBAL,omitempty"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+type ofxTransaction struct {
+	TrnType      string       `xml:"TRNTYPE"`
+	DTPosted     string       `xml:"DTPOSTED"`
+	DTUser       string       `xml:"DTUSER,omitempty"`
+	DTAvail      string       `xml:"DTAVAIL,omitempty"`
+	TrnAmt       string       `xml:"TRNAMT"`
+	FITID        string       `xml:"FITID"`
+	RefNum       string       `xml:"REFNUM,omitempty"`
+	Name         string       `xml:"NAME,omitempty"`
+	Memo         string       `xml:"MEMO,omitempty"`
+	OrigCurrency *ofxCurrency `xml:"ORIGCURRENCY,omitempty"`
+}
+
+type ofxCurrency struct {
+	CurRate string `xml:"CURRATE"`
+	CurSym  string `xml:"CURSYM"`
+}
+
+func newOFXDocument(s Statement) (ofxDocument, error) {
+	var document ofxDocument
+
+	currency := s.Account.Currency
+	if len(currency) != 3 {
+		return document, fmt.Errorf("account currency %q must be an ISO 4217 currency code", currency)
+	}
+
+	createdAt := s.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	var booked []ais.Transaction
+	for _, t := range s.Transactions {
+		if !t.Data().Status.IsPending() {
+			booked = append(booked, t)
+		}
+	}
+	fingerprints := ais.Fingerprints(booked)
+
+	bankID, err := ofxBankID(s.Account)
+	if err != nil {
+		return document, err
+	}
+	reply := ofxStatementReply{CurDef: currency}
+	reply.BankAccount.BankID = bankID
+	reply.BankAccount.AcctID = ofxAccountID(s.Account)
+	reply.BankAccount.AcctType = "CHECKING"
+	if strings.Contains(strings.ToLower(s.Account.AccountType+" "+s.Account.Product), "saving") {
+		reply.BankAccount.AcctType = "SAVINGS"
+	}
+
+	from, to := s.From, s.To
+	for i, t := range booked {
+		data := t.Data()
+		if !strings.EqualFold(data.Currency, currency) {
+			return document, fmt.Errorf("transaction %s has currency %s, the account currency is %s", data.TransactionID, data.Currency, currency)
+		}
+
+		posted := firstSetDate(data.BookingDate, data.ValueDate, data.TransactionDate)
+		if posted.IsZero() {
+			return document, fmt.Errorf("transaction %s has no booking date", data.TransactionID)
+		}
+		if s.From.IsZero() && (from.IsZero() || posted.Before(from)) {
+			from = posted
+		}
+		if s.To.IsZero() && (to.IsZero() || posted.After(to)) {
+			to = posted
+		}
+
+		fitID := data.TransactionID
+		if len(fitID) == 0 {
+			fitID = fingerprints[i]
+		}
+		transaction := ofxTransaction{
+			TrnType:  OFXTransactionType(t),
+			DTPosted: ofxDate(posted),
+			DTUser:   ofxDate(data.TransactionDate),
+			DTAvail:  ofxDate(data.ValueDate),
+			TrnAmt:   t.SignedAmount().Amount.String(),
+			FITID:    truncate(fitID, 255),
+			RefNum:   truncate(data.Reference, 32),
+			Name:     truncate(firstNonEmpty(data.CounterpartyName, data.Narrative), 32),
+			Memo:     truncate(strings.Join(nonEmpty(data.Narrative, data.Message), " "), 255),
+		}
+		if transaction.Memo == transaction.Name {
+			transaction.Memo = ""
+		}
+		if original := data.OriginalAmount(); len(original.Currency) == 3 && !strings.EqualFold(original.Currency, currency) {
+			if rate := currencyRate(data); rate.IsSet() {
+				transaction.OrigCurrency = &ofxCurrency{CurRate: rate.String(), CurSym: strings.ToUpper(original.Currency)}
+			}
+		}
+		reply.TransactionList.Transactions = append(reply.TransactionList.Transactions, transaction)
+	}
+
+	if from.IsZero() || to.IsZero() {
+		from = nordeago.DateOf(createdAt, nordeago.Stockholm)
+		to = from
+	}
+	reply.TransactionList.DTStart = ofxDate(from)
+	reply.TransactionList.DTEnd = ofxDate(to)
+
+	ledger := s.ClosingBalance.Amount
+	if !ledger.IsSet() {
+		ledger = s.Account.BookedBalance
+	}
+	if !ledger.IsSet() {
+		ledger = nordeago.NewDecimal(0, 0)
+	}
+	asOf := ofxDateTime(createdAt)
+	reply.LedgerBalance = ofxBalance{Amount: ledger.String(), DTAsOf: asOf}
+	if s.Account.AvailableBalance.IsSet() {
+		reply.AvailableBalance = &ofxBalance{Amount: s.Account.AvailableBalance.String(), DTAsOf: asOf}
+	}
+
+	success := ofxStatus{Code: 0, Severity: "INFO"}
+	document.Signon.Response.Status = success
+	document.Signon.Response.DTServer = asOf
+	document.Signon.Response.Language = "ENG"
+	document.Bank.Transaction.TrnUID = "0"
+	document.Bank.Transaction.Status = success
+	document.Bank.Transaction.Statement = reply
+	return document, nil
+}
+
+// ibanBankCodeLengths is the length of the bank code at the start of the national part of an IBAN
+var ibanBankCodeLengths = map[string]int{"SE": 3, "FI": 3, "DK": 4, "NO": 4}
+
+// ofxBankID returns the first 8 characters of the BIC since OFX limits the bank id to 9 characters. Accounts without
+// a BIC use the bank code of the IBAN, which is converted from the national account number when needed.
+func ofxBankID(account ais.AccountDetailed) (string, error) {
+	if bic := strings.TrimSpace(account.Bank.BIC); len(bic) > 0 {
+		return truncate(bic, 8), nil
+	}
+
+	for _, number := range append([]ais.AccountNumber{account.AccountNumber}, account.AccountNumbers...) {
+		value := number.Value
+		accountType := strings.ToUpper(number.Type)
+		if country, ok := strings.CutPrefix(accountType, "BBAN_"); ok {
+			converted, err := iban.FromBBAN(country, value)
+			if err != nil {
+				continue
+			}
+			value, accountType = converted, "IBAN"
+		}
+		if accountType != "IBAN" || iban.Validate(value) != nil {
+			continue
+		}
+		value = iban.Normalize(value)
+		if length, ok := ibanBankCodeLengths[value[:2]]; ok {
+			return value[4 : 4+length], nil
+		}
+	}
+	return "", fmt.Errorf("account %s has no BIC or Nordic account number to use as the OFX bank id", account.ID)
+}
+
+// ofxAccountID returns an account number that fits in the 22 characters allowed by OFX, preferring the national
+// account number since IBANs can be longer
+func ofxAccountID(account ais.AccountDetailed) string {
+	numbers := append([]ais.AccountNumber{account.AccountNumber}, account.AccountNumbers...)
+	for _, number := range numbers {
+		if strings.HasPrefix(strings.ToUpper(number.Type), "BBAN") && len(number.Value) > 0 && len(number.Value) <= 22 {
+			return number.Value
+		}
+	}
+	for _, number := range numbers {
+		if len(number.Value) > 0 && len(number.Value) <= 22 {
+			return number.Value
+		}
+	}
+	return truncate(accountNumber(account), 22)
+}
+
+func ofxDate(d nordeago.Date) string {
+	return formatDate(d, "20060102")
+}
+
+// ofxDateTime formats the time in UTC with the time zone offset and name in brackets
+func ofxDateTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:UTC]"
+}
+
+func firstSetDate(dates ...nordeago.Date) nordeago.Date {
+	for _, d := range dates {
+		if !d.IsZero() {
+			return d
+		}
+	}
+	return nordeago.Date{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(strings.TrimSpace(v)) > 0 {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// currencyRate returns the rate from the original currency to the account currency. The rate is derived from the
+// amounts when the API does not return it, and is unset when neither is available.
+func currencyRate(data *ais.TransactionData) nordeago.Decimal {
+	if data.CurrencyRate.IsSet() && data.CurrencyRate.Sign() != 0 {
+		return data.CurrencyRate
+	}
+	if data.OriginalCurrencyAmount.Sign() == 0 || data.Amount.Sign() == 0 {
+		return nordeago.Decimal{}
+	}
+	return data.Amount.Abs().Quo(data.OriginalCurrencyAmount.Abs(), 6)
+}
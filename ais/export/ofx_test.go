@@ -0,0 +1,189 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func TestWriteOFX(t *testing.T) {
+	transactions := testTransactions()
+	noID := *transactions[1].Data()
+	noID.TransactionID = ""
+	noID.OriginalCurrency = "EUR"
+	noID.OriginalCurrencyAmount = nordeago.MustParseDecimal("5.50")
+	noID.CurrencyRate = nordeago.MustParseDecimal("10.89")
+	pending := noID
+	pending.Status = ais.TransactionStatusPending
+	transactions = append(transactions, &ais.DebitTransaction{TransactionData: noID}, &ais.DebitTransaction{TransactionData: pending})
+
+	var b bytes.Buffer
+	err := WriteOFX(&b, Statement{CreatedAt: time.Date(2018, 10, 4, 8, 0, 0, 0, time.UTC), Account: testAccount(), Transactions: transactions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `<?OFX OFXHEADER="200" VERSION="220"`) {
+		t.Errorf("header was incorrect, got: %s, want: an OFX 2.2 header.", b.String())
+	}
+
+	var document struct {
+		Signon struct {
+			DTServer string `xml:"SONRS>DTSERVER"`
+		} `xml:"SIGNONMSGSRSV1"`
+		Statement struct {
+			CurDef       string `xml:"CURDEF"`
+			AcctID       string `xml:"BANKACCTFROM>ACCTID"`
+			BankID       string `xml:"BANKACCTFROM>BANKID"`
+			DTStart      string `xml:"BANKTRANLIST>DTSTART"`
+			DTEnd        string `xml:"BANKTRANLIST>DTEND"`
+			Transactions []struct {
+				TrnType  string `xml:"TRNTYPE"`
+				DTPosted string `xml:"DTPOSTED"`
+				TrnAmt   string `xml:"TRNAMT"`
+				FITID    string `xml:"FITID"`
+				Name     string `xml:"NAME"`
+				CurSym   string `xml:"ORIGCURRENCY>CURSYM"`
+				CurRate  string `xml:"ORIGCURRENCY>CURRATE"`
+			} `xml:"BANKTRANLIST>STMTTRN"`
+			LedgerBalance string `xml:"LEDGERBAL>BALAMT"`
+			Available     string `xml:"AVAILBAL>BALAMT"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS"`
+	}
+	if err := xml.Unmarshal(b.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	s := document.Statement
+	if s.CurDef != "SEK" || s.AcctID != "41770042136" || s.BankID != "NDEASESS" || s.DTStart != "20181001" || s.DTEnd != "20181002" {
+		t.Errorf("statement was incorrect, got: %+v.", s)
+	}
+	if document.Signon.DTServer != "20181004080000.000[0:UTC]" {
+		t.Errorf("DTSERVER was incorrect, got: %s, want: %s.", document.Signon.DTServer, "20181004080000.000[0:UTC]")
+	}
+	if s.LedgerBalance != "1000.00" || s.Available != "950.00" {
+		t.Errorf("balances were incorrect, got: %s and %s, want: 1000.00 and 950.00.", s.LedgerBalance, s.Available)
+	}
+	if len(s.Transactions) != 3 {
+		t.Fatalf("transactions were incorrect, got: %d, want: 3 booked transactions.", len(s.Transactions))
+	}
+	if tr := s.Transactions[0]; tr.TrnType != OFXTypeCredit || tr.TrnAmt != "25000.00" || tr.FITID != "1" || tr.DTPosted != "20181001" {
+		t.Errorf("credit was incorrect, got: %+v.", tr)
+	}
+	if tr := s.Transactions[1]; tr.TrnType != OFXTypePOS || tr.TrnAmt != "-59.90" || tr.Name != "ICA Maxi; Stockholm" {
+		t.Errorf("debit was incorrect, got: %+v.", tr)
+	}
+	// The transaction has the same fingerprint as the previous one on the same day
+	if tr := s.Transactions[2]; tr.FITID != ais.Fingerprint(transactions[1])+"#2" || tr.CurSym != "EUR" || tr.CurRate != "10.89" {
+		t.Errorf("transaction without id was incorrect, got: %+v, want: a numbered fingerprint FITID and EUR as original currency.", tr)
+	}
+}
+
+func TestWriteOFXCurrencyMismatch(t *testing.T) {
+	transactions := testTransactions()
+	transactions[1].Data().Currency = "EUR"
+	if err := WriteOFX(&bytes.Buffer{}, Statement{Account: testAccount(), Transactions: transactions}); err == nil {
+		t.Errorf("WriteOFX was incorrect, got: nil, want: currency mismatch error.")
+	}
+}
+
+func TestWriteOFXCurrencyRate(t *testing.T) {
+	derived := *testTransactions()[1].Data()
+	derived.OriginalCurrency = "EUR"
+	derived.OriginalCurrencyAmount = nordeago.MustParseDecimal("5.50")
+	missing := derived
+	missing.TransactionID = "3"
+	missing.OriginalCurrencyAmount = nordeago.Decimal{}
+
+	var b bytes.Buffer
+	transactions := []ais.Transaction{&ais.DebitTransaction{TransactionData: derived}, &ais.DebitTransaction{TransactionData: missing}}
+	if err := WriteOFX(&b, Statement{Account: testAccount(), Transactions: transactions}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "<CURRATE>10.890909</CURRATE>") || strings.Count(b.String(), "<ORIGCURRENCY>") != 1 {
+		t.Errorf("ORIGCURRENCY was incorrect, got: %s, want: a single derived rate of 10.890909.", b.String())
+	}
+}
+
+func TestOFXTransactionType(t *testing.T) {
+	tests := []struct {
+		transaction ais.Transaction
+		valid       string
+	}{
+		{&ais.CreditTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("100"), Narrative: "Lön"}}, OFXTypeCredit},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("100"), Narrative: "Autogiro Telia"}}, OFXTypeDebit},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("500"), Narrative: "Kontantuttag Bankomat Centralen", CardNumber: "1234"}}, OFXTypeATM},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("20"), Narrative: "KÄTEISNOSTO OTTO"}}, OFXTypeATM},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("25"), TypeDescription: "Avgift"}}, OFXTypeFee},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("25"), TypeDescription: "Månadsavgift"}}, OFXTypeDebit},
+		{&ais.CreditTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("25"), TypeDescription: "Avgift", Narrative: "Återbetald"}}, OFXTypeCredit},
+		{&ais.CreditTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("59.90"), Narrative: "Kortköp retur", CardNumber: "1234"}}, OFXTypeCredit},
+		{&ais.CreditTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("3.12"), Narrative: "Ränta"}}, OFXTypeInterest},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("59.90"), Narrative: "ICA Maxi", CardNumber: "1234"}}, OFXTypePOS},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("1000"), Narrative: "Överföring sparkonto"}}, OFXTypeTransfer},
+		{&ais.DebitTransaction{TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("10"), Narrative: "Feel Good Café"}}, OFXTypeDebit},
+		{&ais.UnknownTransaction{Type: "New", TransactionData: ais.TransactionData{Amount: nordeago.MustParseDecimal("-10")}}, OFXTypeDebit},
+	}
+	for _, test := range tests {
+		if trnType := OFXTransactionType(test.transaction); trnType != test.valid {
+			t.Errorf("OFXTransactionType(%s) was incorrect, got: %s, want: %s.", test.transaction.Data().Narrative, trnType, test.valid)
+		}
+	}
+}
+
+func TestWriteOFXBankID(t *testing.T) {
+	withIBAN := testAccount()
+	withIBAN.Bank.BIC = ""
+	withBBAN := withIBAN
+	withBBAN.AccountNumbers = nil
+	withoutNumbers := withBBAN
+	withoutNumbers.AccountNumber = ais.AccountNumber{Type: "BGNR", Value: "5050-1055"}
+
+	tests := []struct {
+		account ais.AccountDetailed
+		valid   string
+	}{
+		{testAccount(), "NDEASESS"},
+		{withIBAN, "300"},
+		{withBBAN, "300"},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := WriteOFX(&b, Statement{Account: test.account, Transactions: testTransactions()}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), "<BANKID>"+test.valid+"</BANKID>") {
+			t.Errorf("BANKID was incorrect, got: %s, want: %s.", b.String(), test.valid)
+		}
+	}
+
+	if err := WriteOFX(&bytes.Buffer{}, Statement{Account: withoutNumbers, Transactions: testTransactions()}); err == nil {
+		t.Errorf("WriteOFX was incorrect, got: nil, want: an error for an account without a bank id.")
+	}
+}
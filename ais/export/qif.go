@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// QIFOptions configures the output of WriteQIF
+type QIFOptions struct {
+	// DateFormat is the layout used for dates, defaults to 01/02/2006 which is understood by most finance software
+	DateFormat string
+	// AccountType is the QIF account type, defaults to Bank
+	AccountType string
+}
+
+// WriteQIF writes the booked transactions in the Quicken Interchange Format. QIF has no currencies so all
+// transactions must be in the currency of the account, the account header is written when account is not nil.
+// Pending transactions are left out since finance software does not update imported transactions.
+func WriteQIF(w io.Writer, account *ais.AccountDetailed, transactions []ais.Transaction, options QIFOptions) error {
+	if len(options.DateFormat) == 0 {
+		options.DateFormat = "01/02/2006"
+	}
+	if len(options.AccountType) == 0 {
+		options.AccountType = "Bank"
+	}
+
+	currency := ""
+	if account != nil {
+		currency = account.Currency
+	}
+
+	b := bufio.NewWriter(w)
+	if account != nil {
+		fmt.Fprintf(b, "!Account\nN%s\nT%s\n", qifText(firstNonEmpty(account.AccountName, accountNumber(*account))), options.AccountType)
+		if len(account.AccountName) > 0 {
+			fmt.Fprintf(b, "D%s\n", qifText(accountNumber(*account)))
+		}
+		b.WriteString("^\n")
+	}
+	fmt.Fprintf(b, "!Type:%s\n", options.AccountType)
+
+	for _, t := range transactions {
+		data := t.Data()
+		if data.Status.IsPending() {
+			continue
+		}
+		if len(currency) == 0 {
+			currency = data.Currency
+		}
+		if !strings.EqualFold(data.Currency, currency) {
+			return fmt.Errorf("transaction %s has currency %s, all transactions must be in %s", data.TransactionID, data.Currency, currency)
+		}
+
+		date := firstSetDate(data.BookingDate, data.ValueDate, data.TransactionDate)
+		if date.IsZero() {
+			return fmt.Errorf("transaction %s has no booking date", data.TransactionID)
+		}
+
+		fmt.Fprintf(b, "D%s\n", formatDate(date, options.DateFormat))
+		fmt.Fprintf(b, "T%s\n", t.SignedAmount().Amount.String())
+		if reference := qifText(data.Reference); len(reference) > 0 {
+			fmt.Fprintf(b, "N%s\n", reference)
+		}
+		if payee := qifText(firstNonEmpty(data.CounterpartyName, data.Narrative)); len(payee) > 0 {
+			fmt.Fprintf(b, "P%s\n", payee)
+		}
+		if memo := qifText(strings.Join(nonEmpty(data.Narrative, data.Message), " ")); len(memo) > 0 && memo != qifText(firstNonEmpty(data.CounterpartyName, data.Narrative)) {
+			fmt.Fprintf(b, "M%s\n", memo)
+		}
+		b.WriteString("^\n")
+	}
+	return b.Flush()
+}
+
+// qifText removes line breaks since every QIF field is a single line
+func qifText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
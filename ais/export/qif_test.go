@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteQIF(t *testing.T) {
+	var b bytes.Buffer
+	account := testAccount()
+	if err := WriteQIF(&b, &account, testTransactions(), QIFOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "!Account\nNChecking\nTBank\nDSE7030000000041770042136\n^\n!Type:Bank\n" +
+		"D10/01/2018\nT25000.00\nN4711\nPAcme AB\nMLön\n^\n" +
+		"D10/02/2018\nT-59.90\nPICA Maxi; Stockholm\nMKortköp\n^\n"
+	if b.String() != want {
+		t.Errorf("WriteQIF was incorrect, got: %q, want: %q.", b.String(), want)
+	}
+}
+
+func TestWriteQIFMixedCurrencies(t *testing.T) {
+	transactions := testTransactions()
+	transactions[1].Data().Currency = "EUR"
+	if err := WriteQIF(&bytes.Buffer{}, nil, transactions, QIFOptions{}); err == nil {
+		t.Errorf("WriteQIF was incorrect, got: nil, want: currency error.")
+	}
+}
@@ -0,0 +1,224 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// DefaultSIEBankAccount is the BAS ledger account for bank accounts, used when the bank account is not in the mapping
+const DefaultSIEBankAccount = "1930"
+
+// SIEOptions configures WriteSIE
+type SIEOptions struct {
+	// CompanyName is written as #FNAMN, it is required by SIE
+	CompanyName string
+	// OrgNumber is the Swedish organisation number of the company, written as #ORGNR when set
+	OrgNumber string
+	// LedgerAccounts maps the id or an account number of a Nordea account to the ledger account used for it, defaults
+	// to DefaultSIEBankAccount
+	LedgerAccounts map[string]string
+	// Classify returns the classification of a transaction used to find the counter-account, defaults to the type
+	// description of the transaction
+	Classify func(t ais.Transaction) string
+	// CounterAccounts maps a classification to the ledger account booked against the bank account
+	CounterAccounts map[string]string
+	// DefaultCounterAccount is used for classifications that are not in CounterAccounts. When it is empty an
+	// unmapped transaction is an error.
+	DefaultCounterAccount string
+	// AccountNames are written as #KONTO records so that the receiving system can create missing accounts
+	AccountNames map[string]string
+	// Series is the voucher series, defaults to A
+	Series string
+	// FirstNumber is the number of the first voucher, defaults to 1
+	FirstNumber int
+	// Checksum adds #KSUMMA records with a CRC-32 checksum of the file
+	Checksum bool
+	// GeneratedAt defaults to the current time
+	GeneratedAt time.Time
+}
+
+// WriteSIE writes the booked transactions of the account as SIE 4 vouchers. The file is meant to be imported into an
+// accounting system, it is an SIE 4I file with vouchers but without the fiscal years, chart of accounts type and
+// balances of a complete SIE 4E export. Every transaction is a #VER record with one #TRANS record for the bank ledger
+// account and one for the counter-account. The file is encoded in code page 437 (PC8) as required by SIE.
+func WriteSIE(w io.Writer, account ais.AccountDetailed, transactions []ais.Transaction, options SIEOptions) error {
+	if len(strings.TrimSpace(options.CompanyName)) == 0 {
+		return fmt.Errorf("company name is required by SIE")
+	}
+	bankAccount := sieLedgerAccount(account, options.LedgerAccounts)
+	series := options.Series
+	if len(series) == 0 {
+		series = "A"
+	}
+	number := options.FirstNumber
+	if number == 0 {
+		number = 1
+	}
+	classify := options.Classify
+	if classify == nil {
+		classify = func(t ais.Transaction) string {
+			return t.Data().TypeDescription
+		}
+	}
+	generatedAt := options.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+
+	s := &sieWriter{}
+	s.record("#FLAGGA", "0")
+	if options.Checksum {
+		s.record("#KSUMMA")
+		s.summing = true
+	}
+	s.record("#PROGRAM", sieString("nordeago"), sieString("1.0"))
+	s.record("#FORMAT", "PC8")
+	s.record("#GEN", generatedAt.Format("20060102"))
+	s.record("#SIETYP", "4")
+	if len(options.OrgNumber) > 0 {
+		s.record("#ORGNR", options.OrgNumber)
+	}
+	s.record("#FNAMN", sieString(options.CompanyName))
+	if currency := strings.ToUpper(account.Currency); len(currency) > 0 && currency != "SEK" {
+		s.record("#VALUTA", currency)
+	}
+
+	names := map[string]string{bankAccount: account.AccountName}
+	for ledgerAccount, name := range options.AccountNames {
+		names[ledgerAccount] = name
+	}
+	for _, ledgerAccount := range sortedKeys(names) {
+		if len(names[ledgerAccount]) > 0 {
+			s.record("#KONTO", ledgerAccount, sieString(names[ledgerAccount]))
+		}
+	}
+
+	for _, t := range transactions {
+		data := t.Data()
+		if data.Status.IsPending() {
+			continue
+		}
+		if len(account.Currency) > 0 && !strings.EqualFold(data.Currency, account.Currency) {
+			return fmt.Errorf("transaction %s has currency %s, the account currency is %s", data.TransactionID, data.Currency, account.Currency)
+		}
+		date := firstSetDate(data.BookingDate, data.ValueDate, data.TransactionDate)
+		if date.IsZero() {
+			return fmt.Errorf("transaction %s has no booking date", data.TransactionID)
+		}
+
+		classification := classify(t)
+		counterAccount, ok := options.CounterAccounts[classification]
+		if !ok {
+			counterAccount = options.DefaultCounterAccount
+		}
+		if len(counterAccount) == 0 {
+			return fmt.Errorf("transaction %s has classification %q which is not mapped to a counter-account", data.TransactionID, classification)
+		}
+
+		amount := t.SignedAmount().Round().Amount
+		text := firstNonEmpty(data.CounterpartyName, data.Narrative, data.Message)
+		s.record("#VER", sieString(series), sieString(fmt.Sprint(number)), formatDate(date, "20060102"), sieString(text))
+		s.line("{")
+		s.record("   #TRANS", bankAccount, "{}", amount.String())
+		s.record("   #TRANS", counterAccount, "{}", amount.Neg().String())
+		s.line("}")
+		number++
+	}
+
+	if options.Checksum {
+		s.summing = false
+		s.record("#KSUMMA", fmt.Sprint(s.crc))
+	}
+	_, err := w.Write(s.buf.Bytes())
+	return err
+}
+
+// sieWriter builds a SIE file and calculates the checksum over the labels and field contents of the records, without
+// the spaces, quotes and braces that separate them
+type sieWriter struct {
+	buf     bytes.Buffer
+	summing bool
+	crc     uint32
+}
+
+func (s *sieWriter) record(label string, fields ...string) {
+	s.line(strings.Join(append([]string{label}, fields...), " "))
+	if !s.summing {
+		return
+	}
+	s.sum(strings.TrimSpace(label))
+	for _, field := range fields {
+		field = strings.Trim(field, "{}")
+		if unquoted, ok := strings.CutPrefix(field, `"`); ok {
+			field = strings.ReplaceAll(strings.TrimSuffix(unquoted, `"`), `\"`, `"`)
+		}
+		s.sum(field)
+	}
+}
+
+func (s *sieWriter) line(line string) {
+	s.buf.Write(encodeCP437(line))
+	s.buf.WriteString("\r\n")
+}
+
+func (s *sieWriter) sum(value string) {
+	s.crc = crc32.Update(s.crc, crc32.IEEETable, encodeCP437(value))
+}
+
+// sieString quotes a field and escapes quotes in it, line breaks are not allowed in SIE fields
+func sieString(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// sieLedgerAccount returns the ledger account mapped to the id or one of the account numbers of the account
+func sieLedgerAccount(account ais.AccountDetailed, mapping map[string]string) string {
+	keys := []string{account.ID, account.AccountNumber.Value}
+	for _, number := range account.AccountNumbers {
+		keys = append(keys, number.Value)
+	}
+	for _, key := range keys {
+		if ledgerAccount, ok := mapping[key]; ok && len(key) > 0 {
+			return ledgerAccount
+		}
+	}
+	return DefaultSIEBankAccount
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
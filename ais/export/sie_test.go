@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func TestWriteSIE(t *testing.T) {
+	transactions := testTransactions()
+	transactions[0].Data().TypeDescription = "Lön"
+	transactions[1].Data().TypeDescription = "Kortköp"
+
+	var b bytes.Buffer
+	err := WriteSIE(&b, testAccount(), transactions, SIEOptions{
+		CompanyName:     "Företaget AB",
+		OrgNumber:       "556000-0000",
+		LedgerAccounts:  map[string]string{"41770042136": "1940"},
+		CounterAccounts: map[string]string{"Lön": "3010", "Kortköp": "4010"},
+		AccountNames:    map[string]string{"3010": "Försäljning", "4010": "Inköp"},
+		GeneratedAt:     time.Date(2018, 10, 4, 8, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"#FLAGGA 0",
+		`#PROGRAM "nordeago" "1.0"`,
+		"#FORMAT PC8",
+		"#GEN 20181004",
+		"#SIETYP 4",
+		"#ORGNR 556000-0000",
+		`#FNAMN "F` + "\x94" + `retaget AB"`,
+		`#KONTO 1940 "Checking"`,
+		`#KONTO 3010 "F` + "\x94" + `rs` + "\x84" + `ljning"`,
+		`#KONTO 4010 "Ink` + "\x94" + `p"`,
+		`#VER "A" "1" 20181001 "Acme AB"`,
+		"{",
+		"   #TRANS 1940 {} 25000.00",
+		"   #TRANS 3010 {} -25000.00",
+		"}",
+		`#VER "A" "2" 20181002 "ICA Maxi; Stockholm"`,
+		"{",
+		"   #TRANS 1940 {} -59.90",
+		"   #TRANS 4010 {} 59.90",
+		"}",
+		"",
+	}, "\r\n")
+	if b.String() != want {
+		t.Errorf("WriteSIE was incorrect, got: %q, want: %q.", b.String(), want)
+	}
+}
+
+func TestWriteSIEChecksum(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteSIE(&b, testAccount(), testTransactions()[:1], SIEOptions{
+		CompanyName:           "Acme AB",
+		DefaultCounterAccount: "3010",
+		GeneratedAt:           time.Date(2018, 10, 4, 8, 0, 0, 0, time.UTC),
+		Checksum:              true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if lines[1] != "#KSUMMA" || !strings.HasPrefix(lines[len(lines)-1], "#KSUMMA ") {
+		t.Fatalf("WriteSIE was incorrect, got: %q, want: #KSUMMA records around the content.", lines)
+	}
+
+	// The checksum covers the labels and field contents without separators, quotes and braces
+	content := `#PROGRAMnordeago1.0#FORMATPC8#GEN20181004#SIETYP4#FNAMNAcme AB#KONTO1930Checking#VERA120181001Acme AB#TRANS193025000.00#TRANS3010-25000.00`
+	want := fmt.Sprintf("#KSUMMA %d", crc32.ChecksumIEEE([]byte(content)))
+	if lines[len(lines)-1] != want {
+		t.Errorf("checksum was incorrect, got: %s, want: %s.", lines[len(lines)-1], want)
+	}
+}
+
+func TestWriteSIEUnmappedClassification(t *testing.T) {
+	err := WriteSIE(&bytes.Buffer{}, testAccount(), testTransactions(), SIEOptions{
+		CompanyName: "Acme AB",
+		Classify:    func(t ais.Transaction) string { return t.TransactionType() },
+	})
+	if err == nil || !strings.Contains(err.Error(), "CreditTransaction") {
+		t.Errorf("WriteSIE was incorrect, got: %v, want: unmapped classification error.", err)
+	}
+}
+
+func TestWriteSIECompanyName(t *testing.T) {
+	err := WriteSIE(&bytes.Buffer{}, testAccount(), testTransactions(), SIEOptions{DefaultCounterAccount: "3010"})
+	if err == nil {
+		t.Errorf("WriteSIE was incorrect, got: nil, want: an error for a missing company name.")
+	}
+}
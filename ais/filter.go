@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// transactionDateFormat is the format of the fromDate and toDate query parameters
+const transactionDateFormat = "2006-01-02"
+
+// TransactionFilter is used with the GetAccountTransactions method to list transactions for the specified account id.
+// All fields are optional, dates are sent as calendar dates in the location of the time value.
+type TransactionFilter struct {
+	FromDate        time.Time
+	ToDate          time.Time
+	Language        string // Two letter language code of the narratives, for example en or sv
+	Status          TransactionStatus
+	ContinuationKey string // Returned by a previous request when there are more transactions
+}
+
+// Validate checks that the date range and the other parameters can be sent to the API
+func (f TransactionFilter) Validate() error {
+	if !f.FromDate.IsZero() && !f.ToDate.IsZero() && f.ToDate.Before(f.FromDate) {
+		return fmt.Errorf("toDate %s is before fromDate %s", f.ToDate.Format(transactionDateFormat), f.FromDate.Format(transactionDateFormat))
+	}
+	if len(f.Language) > 0 && !isLanguageCode(f.Language) {
+		return fmt.Errorf("language %q is not a two letter language code", f.Language)
+	}
+	switch f.Status {
+	case "", TransactionStatusBooked, TransactionStatusPending:
+	default:
+		return fmt.Errorf("unknown transaction status %q", f.Status)
+	}
+	return nil
+}
+
+// Values validates the filter and returns it encoded as query parameters
+func (f TransactionFilter) Values() (url.Values, error) {
+	if err := f.Validate(); err != nil {
+		return nil, errors.New("invalid transaction filter: " + err.Error())
+	}
+
+	v := url.Values{}
+	if !f.FromDate.IsZero() {
+		v.Set("fromDate", f.FromDate.Format(transactionDateFormat))
+	}
+	if !f.ToDate.IsZero() {
+		v.Set("toDate", f.ToDate.Format(transactionDateFormat))
+	}
+	if len(f.Language) > 0 {
+		v.Set("language", f.Language)
+	}
+	if len(f.Status) > 0 {
+		v.Set("status", string(f.Status))
+	}
+	if len(f.ContinuationKey) > 0 {
+		v.Set("continuationKey", f.ContinuationKey)
+	}
+	return v, nil
+}
+
+func isLanguageCode(language string) bool {
+	if len(language) != 2 {
+		return false
+	}
+	for _, r := range language {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
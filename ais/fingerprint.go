@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// Fingerprint returns a deterministic identifier for a transaction that does not depend on the transaction id. It is
+// calculated from the booking date, the signed amount, the currency, the counterparty, the reference and the
+// narrative. Two identical transactions on the same day have the same fingerprint, use Fingerprints to tell them
+// apart.
+func Fingerprint(t Transaction) string {
+	data := t.Data()
+	date := data.BookingDate
+	if date.IsZero() {
+		date = data.TransactionDate
+	}
+
+	fields := []string{
+		dateKey(date),
+		amountKey(t.SignedAmount().Amount),
+		strings.ToUpper(data.Currency),
+		textKey(data.CounterpartyName),
+		textKey(data.Reference),
+		textKey(data.Narrative),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Fingerprints returns the fingerprints of the transactions in order. When several transactions have the same
+// fingerprint the second one gets the suffix #2, the third #3 and so on. The numbering is stable between fetches as
+// long as whole days are fetched, which is always the case since the API filters by date.
+func Fingerprints(transactions []Transaction) []string {
+	counts := make(map[string]int)
+	fingerprints := make([]string, len(transactions))
+	for i, t := range transactions {
+		fingerprint := Fingerprint(t)
+		counts[fingerprint]++
+		if n := counts[fingerprint]; n > 1 {
+			fingerprint = fmt.Sprintf("%s#%d", fingerprint, n)
+		}
+		fingerprints[i] = fingerprint
+	}
+	return fingerprints
+}
+
+// Dedupe merges transaction lists from overlapping fetches and removes the transactions that were returned more than
+// once. Transactions are matched on their fingerprints so that empty or unstable transaction ids do not matter, two
+// identical transactions on the same day in one list are both kept.
+func Dedupe(lists ...TransactionList) TransactionList {
+	seen := make(map[string]bool)
+	var result TransactionList
+	for _, list := range lists {
+		for i, fingerprint := range Fingerprints(list) {
+			if seen[fingerprint] {
+				continue
+			}
+			seen[fingerprint] = true
+			result = append(result, list[i])
+		}
+	}
+	return result
+}
+
+func dateKey(d nordeago.Date) string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year(), d.Month(), d.Day())
+}
+
+// amountKey formats the amount without trailing zeros so that 10.5 and 10.50 are the same amount
+func amountKey(d nordeago.Decimal) string {
+	s := d.String()
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// textKey ignores case and differences in whitespace
+func textKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func debit(id string, date string, amount string, counterparty string) Transaction {
+	return &DebitTransaction{TransactionData{
+		TransactionID:    id,
+		BookingDate:      nordeago.MustParseDate(date),
+		Amount:           nordeago.MustParseDecimal(amount),
+		Currency:         "SEK",
+		CounterpartyName: counterparty,
+	}}
+}
+
+func TestFingerprintIgnoresIDAndFormatting(t *testing.T) {
+	a := debit("1", "2018-10-01", "-10.50", "ICA Maxi")
+	b := debit("", "20181001", "10.5", "  ica   maxi ")
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Errorf("Fingerprint was incorrect, got: %s and %s, want: equal fingerprints.", Fingerprint(a), Fingerprint(b))
+	}
+
+	credit := &CreditTransaction{*b.Data()}
+	if Fingerprint(a) == Fingerprint(credit) {
+		t.Errorf("Fingerprint was incorrect, got: equal fingerprints for a debit and a credit, want: different.")
+	}
+	if Fingerprint(a) == Fingerprint(debit("1", "2018-10-02", "10.50", "ICA Maxi")) {
+		t.Errorf("Fingerprint was incorrect, got: equal fingerprints for different dates, want: different.")
+	}
+}
+
+func TestFingerprintsNumbersCollisions(t *testing.T) {
+	fingerprints := Fingerprints([]Transaction{
+		debit("", "2018-10-01", "10", "SL"),
+		debit("", "2018-10-01", "10", "SL"),
+		debit("", "2018-10-01", "20", "SL"),
+	})
+	if strings.Contains(fingerprints[0], "#") || !strings.HasSuffix(fingerprints[1], "#2") || fingerprints[1] != fingerprints[0]+"#2" {
+		t.Errorf("Fingerprints were incorrect, got: %v, want: the second fingerprint with suffix #2.", fingerprints)
+	}
+	if strings.Contains(fingerprints[2], "#") {
+		t.Errorf("Fingerprints were incorrect, got: %s, want: no suffix.", fingerprints[2])
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	first := TransactionList{
+		debit("a", "2018-10-01", "10", "SL"),
+		debit("b", "2018-10-01", "10", "SL"),
+		debit("c", "2018-10-02", "99", "ICA"),
+	}
+	// The overlapping fetch returns the same transactions with new ids and one new transaction
+	second := TransactionList{
+		debit("x", "2018-10-01", "10", "SL"),
+		debit("y", "2018-10-01", "10", "SL"),
+		debit("z", "2018-10-02", "99", "ICA"),
+		debit("w", "2018-10-03", "5", "Pressbyrån"),
+	}
+
+	result := Dedupe(first, second)
+	if len(result) != 4 {
+		t.Fatalf("Dedupe was incorrect, got: %d transactions, want: 4.", len(result))
+	}
+	if result[3].Data().TransactionID != "w" {
+		t.Errorf("Dedupe was incorrect, got: %s, want: w.", result[3].Data().TransactionID)
+	}
+}
@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// TransactionsIterator fetches the transactions of an account page by page, following the continuation key returned
+// by the API until there are no more transactions
+//
+//	it := ais.NewTransactionsIterator(ctx, &client, accountID, ais.TransactionFilter{})
+//	for it.Next() {
+//		fmt.Println(it.Transaction())
+//	}
+//	if err := it.Err(); err != nil {
+//		panic(err)
+//	}
+type TransactionsIterator struct {
+	// Limit stops the iteration after the number of transactions, 0 means no limit
+	Limit int
+	// StopBefore stops the iteration at the first transaction booked before the date. Transactions are returned with
+	// the latest booking date first.
+	StopBefore nordeago.Date
+
+	ctx       context.Context
+	client    *nordeago.Client
+	accountID string
+	filter    TransactionFilter
+
+	page    TransactionList
+	current Transaction
+	count   int
+	fetched bool
+	done    bool
+	err     error
+}
+
+// NewTransactionsIterator creates a TransactionsIterator for the account. The filter is used for the first request,
+// the continuation key of the filter is replaced when fetching the following pages.
+func NewTransactionsIterator(ctx context.Context, c *nordeago.Client, accountID string, filter TransactionFilter) *TransactionsIterator {
+	return &TransactionsIterator{
+		ctx:       ctx,
+		client:    c,
+		accountID: accountID,
+		filter:    filter,
+	}
+}
+
+// Next advances to the next transaction and returns false when there are no more transactions, an error occurred or
+// the context is done
+func (it *TransactionsIterator) Next() bool {
+	for !it.done {
+		if err := it.ctx.Err(); err != nil {
+			it.stop(err)
+			return false
+		}
+
+		if it.Limit > 0 && it.count >= it.Limit {
+			it.stop(nil)
+			return false
+		}
+
+		if len(it.page) > 0 {
+			it.current = it.page[0]
+			it.page = it.page[1:]
+
+			if bookingDate := it.current.Data().BookingDate; !it.StopBefore.IsZero() && !bookingDate.IsZero() && bookingDate.Before(it.StopBefore) {
+				it.stop(nil)
+				return false
+			}
+
+			it.count++
+			return true
+		}
+
+		if it.fetched && len(it.filter.ContinuationKey) == 0 {
+			it.stop(nil)
+			return false
+		}
+
+		it.fetchPage()
+	}
+	return false
+}
+
+// Transaction returns the current transaction
+func (it *TransactionsIterator) Transaction() Transaction {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TransactionsIterator) Err() error {
+	return it.err
+}
+
+// All returns the remaining transactions as an iter.Seq2. Iteration stops after an error is yielded.
+func (it *TransactionsIterator) All() iter.Seq2[Transaction, error] {
+	return func(yield func(Transaction, error) bool) {
+		for it.Next() {
+			if !yield(it.Transaction(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Transactions returns all transactions of the account matching the filter as an iter.Seq2, see TransactionsIterator
+//
+//	for transaction, err := range ais.Transactions(ctx, &client, accountID, ais.TransactionFilter{}) {
+//		if err != nil {
+//			panic(err)
+//		}
+//		fmt.Println(transaction)
+//	}
+func Transactions(ctx context.Context, c *nordeago.Client, accountID string, filter TransactionFilter) iter.Seq2[Transaction, error] {
+	return NewTransactionsIterator(ctx, c, accountID, filter).All()
+}
+
+func (it *TransactionsIterator) fetchPage() {
+	previousKey := it.filter.ContinuationKey
+
+	response, err := GetAccountTransactions(it.ctx, it.client, it.accountID, it.filter)
+	if err != nil {
+		it.stop(err)
+		return
+	}
+
+	// Guard against an API that keeps returning the same page
+	if it.fetched && len(response.ContinuationKey) > 0 && response.ContinuationKey == previousKey {
+		it.stop(fmt.Errorf("continuation key %s was returned twice", previousKey))
+		return
+	}
+
+	it.fetched = true
+	it.page = response.Transactions
+	it.filter.ContinuationKey = response.ContinuationKey
+}
+
+func (it *TransactionsIterator) stop(err error) {
+	it.done = true
+	it.page = nil
+	if it.err == nil {
+		it.err = err
+	}
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func transactionPages() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("continuationKey") {
+		case "":
+			w.Write([]byte(`{"response":{"continuationKey":"page2","transactions":[{"_type":"CreditTransaction","transactionId":"1","bookingDate":"2018-10-03"},{"_type":"CreditTransaction","transactionId":"2","bookingDate":"2018-10-02"}]}}`))
+		case "page2":
+			w.Write([]byte(`{"groupHeader":{"messagePagination":[{"continuationKey":"page3"}]},"response":{"transactions":[{"_type":"CreditTransaction","transactionId":"3","bookingDate":"2018-10-01"}]}}`))
+		default:
+			w.Write([]byte(`{"response":{"transactions":[{"_type":"CreditTransaction","transactionId":"4","bookingDate":"2018-09-30"}]}}`))
+		}
+	}))
+}
+
+func TestTransactionsIteratorFollowsContinuationKey(t *testing.T) {
+	server := transactionPages()
+	defer server.Close()
+
+	c := testClient(server)
+	var ids []string
+	for transaction, err := range Transactions(context.Background(), c, "41770042136", TransactionFilter{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, transaction.Data().TransactionID)
+	}
+	if len(ids) != 4 || ids[3] != "4" {
+		t.Errorf("transactions were incorrect, got: %v, want: [1 2 3 4].", ids)
+	}
+}
+
+func TestTransactionsIteratorStops(t *testing.T) {
+	server := transactionPages()
+	defer server.Close()
+
+	c := testClient(server)
+	it := NewTransactionsIterator(context.Background(), c, "41770042136", TransactionFilter{})
+	it.Limit = 2
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 2 {
+		t.Errorf("Limit was incorrect, got: %d transactions and error %v, want: 2.", count, it.Err())
+	}
+
+	it = NewTransactionsIterator(context.Background(), c, "41770042136", TransactionFilter{})
+	it.StopBefore = nordeago.NewDate(2018, time.October, 2)
+	count = 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 2 {
+		t.Errorf("StopBefore was incorrect, got: %d transactions and error %v, want: 2.", count, it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = NewTransactionsIterator(ctx, c, "41770042136", TransactionFilter{})
+	if it.Next() || it.Err() != context.Canceled {
+		t.Errorf("context cancellation was incorrect, got: %v, want: %v.", it.Err(), context.Canceled)
+	}
+}
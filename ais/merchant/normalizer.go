@@ -0,0 +1,226 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package merchant turns the narratives of card purchases and payments into merchant names, for example
+// "KORTKÖP 240312 ICA NARA ST" into "ICA Nara"
+package merchant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// Alias replaces every name matching the pattern with a fixed name
+type Alias struct {
+	Pattern string `json:"pattern"`
+	Name    string `json:"name"`
+}
+
+// Config contains the case-insensitive regular expressions used by a Normalizer. It can be loaded from JSON.
+type Config struct {
+	// Prefixes are removed from the start of the text, such as KORTKÖP or Korttiosto
+	Prefixes []string `json:"prefixes"`
+	// Removals are removed anywhere in the text, such as dates and card number fragments
+	Removals []string `json:"removals"`
+	// Suffixes are removed from the end of the text until none of them match, such as city names and country codes
+	Suffixes []string `json:"suffixes"`
+	// Aliases are matched against the cleaned name, the first matching alias replaces the name
+	Aliases []Alias `json:"aliases"`
+	// KeepCase leaves the case of the name unchanged, otherwise names are title cased with short words in upper case
+	// kept as they are
+	KeepCase bool `json:"keepCase,omitempty"`
+}
+
+// DefaultConfig returns patterns for the card purchase and payment narratives used by Nordic banks
+func DefaultConfig() Config {
+	return Config{
+		Prefixes: []string{
+			`(kortköp|kortkop|korttiosto|kortkjøp|kortkjop|dankort-køb|dankortköb|visa-køb|visa|mastercard|köp|kjøp|osto|reservation|reserverat|preliminär|varaus)\b[\s:.-]*`,
+			`(swish|mobilepay|vipps)\s+(till|från|fra|til|to|from|betalning|betaling)?\s*`,
+			`(autogiro|e-faktura|efaktura|betalning|bg|pg|avtalegiro|betalingsservice)\s+`,
+		},
+		Removals: []string{
+			`\b\d{6}\b`,
+			`\b\d{1,2}[./]\d{1,2}([./]\d{2,4})?\b`,
+			`\b\d{4}-\d{2}-\d{2}\b`,
+			// Masked card numbers: leading digits, a run of mask characters and trailing digits, where either the
+			// leading or the trailing digits may be missing. The match never runs into letters such as XXL.
+			`(^|[^\pL\d])(\d{4,6}[\s-]?[*x]{2,}([\s-]?[*x]{2,})*([\s-]?\d{2,4})?|[*x]{2,}([\s-]?[*x]{2,})*[\s-]?\d{2,4})([^\pL\d]|$)`,
+			`\b(sek|eur|nok|dkk|usd)\s?\d+([.,]\d+)?\b`,
+			`\b\d+([.,]\d+)?\s?(sek|eur|nok|dkk|usd)\b`,
+		},
+		Suffixes: []string{
+			`\s+(se|swe|fi|fin|no|nor|dk|dnk)$`,
+			`\s+(stockholm|sthlm|st|göteborg|goteborg|gbg|malmö|malmo|uppsala|helsinki|helsingfors|espoo|vantaa|tampere|turku|oulu|oslo|bergen|trondheim|københavn|kobenhavn|copenhagen|aarhus|odense)$`,
+			`[\s,.*/-]+$`,
+		},
+	}
+}
+
+// Normalizer turns transaction texts into merchant names
+type Normalizer struct {
+	prefixes []*regexp.Regexp
+	removals []*regexp.Regexp
+	suffixes []*regexp.Regexp
+	aliases  []compiledAlias
+	keepCase bool
+}
+
+type compiledAlias struct {
+	re   *regexp.Regexp
+	name string
+}
+
+// NewNormalizer compiles the patterns of the configuration
+func NewNormalizer(config Config) (*Normalizer, error) {
+	n := &Normalizer{keepCase: config.KeepCase}
+	var err error
+	if n.prefixes, err = compile(config.Prefixes, "^"); err != nil {
+		return nil, err
+	}
+	if n.removals, err = compile(config.Removals, ""); err != nil {
+		return nil, err
+	}
+	if n.suffixes, err = compile(config.Suffixes, ""); err != nil {
+		return nil, err
+	}
+	for _, alias := range config.Aliases {
+		re, err := regexp.Compile("(?i)" + alias.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias pattern %q: %w", alias.Pattern, err)
+		}
+		n.aliases = append(n.aliases, compiledAlias{re: re, name: alias.Name})
+	}
+	return n, nil
+}
+
+// LoadNormalizer reads a JSON Config and compiles it
+func LoadNormalizer(r io.Reader) (*Normalizer, error) {
+	var config Config
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return nil, err
+	}
+	return NewNormalizer(config)
+}
+
+// DefaultNormalizer returns a Normalizer using DefaultConfig
+func DefaultNormalizer() *Normalizer {
+	n, err := NewNormalizer(DefaultConfig())
+	if err != nil {
+		panic("invalid default merchant patterns: " + err.Error())
+	}
+	return n
+}
+
+// Normalize returns the merchant name in the text, or an empty string if nothing is left after removing the
+// prefixes, dates, card numbers and locations
+func (n *Normalizer) Normalize(text string) string {
+	name := strings.Join(strings.Fields(text), " ")
+
+	for _, re := range n.prefixes {
+		name = strings.TrimSpace(re.ReplaceAllString(name, ""))
+	}
+	for _, re := range n.removals {
+		name = re.ReplaceAllString(name, " ")
+	}
+	name = strings.Join(strings.Fields(name), " ")
+
+	// Remove suffixes one at a time, keeping at least one word so that a merchant named after a city survives
+	for changed := true; changed; {
+		changed = false
+		for _, re := range n.suffixes {
+			trimmed := strings.TrimSpace(re.ReplaceAllString(name, ""))
+			if trimmed != name && len(trimmed) > 0 {
+				name, changed = trimmed, true
+			}
+		}
+	}
+
+	for _, alias := range n.aliases {
+		if alias.re.MatchString(name) {
+			return alias.name
+		}
+	}
+	if n.keepCase {
+		return name
+	}
+	return titleCase(name)
+}
+
+// NormalizeTransaction returns the merchant name of the transaction from the counterparty name, or from the narrative
+// when the counterparty is missing or normalizes to nothing
+func (n *Normalizer) NormalizeTransaction(t ais.Transaction) string {
+	data := t.Data()
+	for _, text := range []string{data.CounterpartyName, data.Narrative, data.Message} {
+		if name := n.Normalize(text); len(name) > 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+// Apply sets the NormalizedCounterparty field of the transactions
+func (n *Normalizer) Apply(transactions []ais.Transaction) {
+	for _, t := range transactions {
+		t.Data().NormalizedCounterparty = n.NormalizeTransaction(t)
+	}
+}
+
+func compile(patterns []string, anchor string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + anchor + "(?:" + pattern + ")")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// titleCase capitalizes each word, words of up to three letters that are all upper case are kept as abbreviations
+// such as ICA or SJ
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		if utf8.RuneCountInString(word) <= 3 && strings.ToUpper(word) == word {
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		capitalize := true
+		for j, r := range runes {
+			if capitalize && unicode.IsLetter(r) {
+				runes[j] = unicode.ToUpper(r)
+			}
+			capitalize = r == '-' || r == '.' || r == '&'
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package merchant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"KORTKÖP 240312 ICA NARA ST", "ICA Nara"},
+		{"Korttiosto 12.03 K-MARKET KAMPPI HELSINKI", "K-Market Kamppi"},
+		{"KORTKJØP 12.03 REMA 1000 OSLO", "Rema 1000"},
+		{"VISA 4567 **** **** SPOTIFY STOCKHOLM SE", "Spotify"},
+		{"Reservation ****1234 SL CENTRALEN STHLM", "SL Centralen"},
+		{"KORTKÖP 1234 **** 5678 ICA MAXI", "ICA Maxi"},
+		{"KORTKÖP 1234XXXXXX5678 XXL SPORT", "XXL Sport"},
+		{"KORTKÖP 1234 **** XXL SPORT", "XXL Sport"},
+		{"KORTKÖP 123456******1234 TAXI STOCKHOLM", "Taxi"},
+		{"Swish till Anna Svensson", "Anna Svensson"},
+		{"SEK 59,90 PRESSBYRAN T-CENTRALEN", "Pressbyran T-Centralen"},
+		{"Stockholm", "Stockholm"},
+		{"KORTKÖP 240312", ""},
+	}
+
+	n := DefaultNormalizer()
+	for _, test := range tests {
+		if got := n.Normalize(test.text); got != test.want {
+			t.Errorf("Normalize was incorrect for %q, got: %q, want: %q.", test.text, got, test.want)
+		}
+	}
+}
+
+func TestLoadNormalizer(t *testing.T) {
+	n, err := LoadNormalizer(strings.NewReader(`{
+		"prefixes": ["kortköp\\s+"],
+		"removals": ["\\b\\d{6}\\b"],
+		"aliases": [{"pattern": "^ica\\b", "name": "ICA"}],
+		"keepCase": true
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Normalize("KORTKÖP 240312 ICA NARA ST"); got != "ICA" {
+		t.Errorf("Normalize was incorrect, got: %q, want: %q.", got, "ICA")
+	}
+	if got := n.Normalize("KORTKÖP 240312 Hemköp"); got != "Hemköp" {
+		t.Errorf("Normalize was incorrect, got: %q, want: %q.", got, "Hemköp")
+	}
+
+	if _, err := NewNormalizer(Config{Prefixes: []string{"("}}); err == nil {
+		t.Errorf("NewNormalizer was incorrect, got: nil, want: invalid pattern error.")
+	}
+}
+
+func TestApply(t *testing.T) {
+	transactions := []ais.Transaction{
+		&ais.DebitTransaction{TransactionData: ais.TransactionData{Narrative: "KORTKÖP 240312 ICA NARA ST"}},
+		&ais.DebitTransaction{TransactionData: ais.TransactionData{CounterpartyName: "TELIA SVERIGE AB", Narrative: "AUTOGIRO"}},
+	}
+	DefaultNormalizer().Apply(transactions)
+
+	if got := transactions[0].Data().NormalizedCounterparty; got != "ICA Nara" {
+		t.Errorf("NormalizedCounterparty was incorrect, got: %q, want: %q.", got, "ICA Nara")
+	}
+	if got := transactions[1].Data().NormalizedCounterparty; got != "Telia Sverige AB" {
+		t.Errorf("NormalizedCounterparty was incorrect, got: %q, want: %q.", got, "Telia Sverige AB")
+	}
+}
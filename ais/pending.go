@@ -0,0 +1,213 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"strings"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// DefaultLinkMaxDays is the default number of days between a pending transaction and the booking that replaces it
+const DefaultLinkMaxDays = 10
+
+// DefaultLinkAmountTolerance is the default relative amount difference allowed for transactions in the account
+// currency, where the booked amount can include a tip or be the final amount of a card authorization
+var DefaultLinkAmountTolerance = nordeago.MustParseDecimal("0.20")
+
+// DefaultLinkFXTolerance is the default relative amount difference allowed for transactions in a foreign currency,
+// where the pending amount is based on an estimated exchange rate
+var DefaultLinkFXTolerance = nordeago.MustParseDecimal("0.05")
+
+// LinkOptions configures how pending transactions are matched to booked transactions
+type LinkOptions struct {
+	// MaxDays is the maximum number of days from the pending transaction to the booking, defaults to
+	// DefaultLinkMaxDays
+	MaxDays int
+	// AmountTolerance is the relative amount difference allowed when both transactions are in the account currency,
+	// defaults to DefaultLinkAmountTolerance
+	AmountTolerance nordeago.Decimal
+	// FXTolerance is the relative amount difference allowed when either transaction was made in a foreign currency,
+	// defaults to DefaultLinkFXTolerance. The larger of the two tolerances is used for such transactions.
+	FXTolerance nordeago.Decimal
+}
+
+// PendingLink is a pending transaction and the booked transaction that replaced it
+type PendingLink struct {
+	Pending Transaction
+	Booked  Transaction
+}
+
+// LinkPending matches pending transactions with the booked transactions that replaced them. The transaction id, the
+// amount and the dates can all change when a transaction is booked so transactions are matched on the transaction id
+// when it is unchanged, otherwise on the type, currency, amount, dates, card number and counterparty. Each booked
+// transaction is linked to at most one pending transaction. The pending transactions that were not linked are
+// returned as well.
+func LinkPending(pending []Transaction, booked []Transaction, options LinkOptions) ([]PendingLink, []Transaction) {
+	used := make([]bool, len(booked))
+	var links []PendingLink
+	var unlinked []Transaction
+
+	for _, p := range pending {
+		best, bestScore := -1, 0
+		for i, b := range booked {
+			if used[i] {
+				continue
+			}
+			if score := options.score(p, b); score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		if best < 0 {
+			unlinked = append(unlinked, p)
+			continue
+		}
+		used[best] = true
+		links = append(links, PendingLink{Pending: p, Booked: booked[best]})
+	}
+
+	return links, unlinked
+}
+
+// Settle removes the pending transactions that have been replaced by a booked transaction in the same list, so that
+// a transaction is not counted twice while it moves from pending to booked
+func Settle(transactions []Transaction, options LinkOptions) TransactionList {
+	var pending, booked []Transaction
+	for _, t := range transactions {
+		if t.Data().Status.IsPending() {
+			pending = append(pending, t)
+		} else {
+			booked = append(booked, t)
+		}
+	}
+
+	links, _ := LinkPending(pending, booked, options)
+	replaced := make(map[Transaction]bool, len(links))
+	for _, link := range links {
+		replaced[link.Pending] = true
+	}
+
+	result := make(TransactionList, 0, len(transactions)-len(replaced))
+	for _, t := range transactions {
+		if !replaced[t] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// score returns how well the booked transaction matches the pending one, 0 means no match
+func (o LinkOptions) score(pending Transaction, booked Transaction) int {
+	p, b := pending.Data(), booked.Data()
+	if len(p.TransactionID) > 0 && p.TransactionID == b.TransactionID {
+		return 100
+	}
+	if pending.TransactionType() != booked.TransactionType() || !strings.EqualFold(p.Currency, b.Currency) {
+		return 0
+	}
+
+	pendingDate, bookedDate := firstDate(p.TransactionDate, p.PaymentDate, p.BookingDate), firstDate(b.BookingDate, b.TransactionDate, b.ValueDate)
+	if !pendingDate.IsZero() && !bookedDate.IsZero() {
+		if bookedDate.Before(pendingDate.AddDays(-1)) || bookedDate.After(pendingDate.AddDays(o.maxDays())) {
+			return 0
+		}
+	}
+
+	score := 0
+	switch {
+	case p.Amount.Abs().Cmp(b.Amount.Abs()) == 0:
+		score += 2
+	case !withinTolerance(p.Amount.Abs(), b.Amount.Abs(), o.tolerance(isForeign(p) || isForeign(b))):
+		return 0
+	}
+
+	if len(p.CardNumber) > 0 && p.CardNumber == b.CardNumber {
+		score += 4
+	}
+	if textMatches(p.CounterpartyName, b.CounterpartyName) || textMatches(p.Narrative, b.Narrative) {
+		score += 2
+	}
+	// An amount that only matches within the tolerance is not enough to link two transactions
+	if score == 0 {
+		return 0
+	}
+	if pendingDate.Equal(bookedDate) {
+		score++
+	}
+	return score
+}
+
+func (o LinkOptions) maxDays() int {
+	if o.MaxDays > 0 {
+		return o.MaxDays
+	}
+	return DefaultLinkMaxDays
+}
+
+// tolerance returns the relative amount difference allowed, foreign transactions use the larger of the tolerances
+func (o LinkOptions) tolerance(foreign bool) nordeago.Decimal {
+	tolerance := o.AmountTolerance
+	if !tolerance.IsSet() {
+		tolerance = DefaultLinkAmountTolerance
+	}
+	if !foreign {
+		return tolerance
+	}
+	fxTolerance := o.FXTolerance
+	if !fxTolerance.IsSet() {
+		fxTolerance = DefaultLinkFXTolerance
+	}
+	if fxTolerance.Cmp(tolerance) > 0 {
+		return fxTolerance
+	}
+	return tolerance
+}
+
+func firstDate(dates ...nordeago.Date) nordeago.Date {
+	for _, d := range dates {
+		if !d.IsZero() {
+			return d
+		}
+	}
+	return nordeago.Date{}
+}
+
+func isForeign(t *TransactionData) bool {
+	return len(t.OriginalCurrency) > 0 && !strings.EqualFold(t.OriginalCurrency, t.Currency)
+}
+
+// withinTolerance returns true if the difference between a and b is at most tolerance times a
+func withinTolerance(a nordeago.Decimal, b nordeago.Decimal, tolerance nordeago.Decimal) bool {
+	return a.Sub(b).Abs().Cmp(a.Mul(tolerance)) <= 0
+}
+
+// textMatches returns true if one of the texts contains the other, ignoring case and whitespace. Pending narratives
+// are often shortened or prefixed compared to the booked narrative.
+func textMatches(a string, b string) bool {
+	a, b = textKey(a), textKey(b)
+	if len(a) < 3 || len(b) < 3 {
+		return false
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func TestTransactionStatusUnmarshal(t *testing.T) {
+	var data TransactionData
+	if err := json.Unmarshal([]byte(`{"status":"Pending"}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	if !data.Status.IsPending() || data.Status.IsBooked() {
+		t.Errorf("Status was incorrect, got: %s, want: %s.", data.Status, TransactionStatusPending)
+	}
+}
+
+func pendingCard(date string, amount string, narrative string) Transaction {
+	return &DebitTransaction{TransactionData{
+		TransactionID:   "pending-" + narrative,
+		Status:          TransactionStatusPending,
+		TransactionDate: nordeago.MustParseDate(date),
+		Amount:          nordeago.MustParseDecimal(amount),
+		Currency:        "SEK",
+		CardNumber:      "1234",
+		Narrative:       narrative,
+	}}
+}
+
+func bookedCard(date string, amount string, narrative string) Transaction {
+	return &DebitTransaction{TransactionData{
+		TransactionID: "booked-" + narrative,
+		Status:        TransactionStatusBooked,
+		BookingDate:   nordeago.MustParseDate(date),
+		Amount:        nordeago.MustParseDecimal(amount),
+		Currency:      "SEK",
+		CardNumber:    "1234",
+		Narrative:     narrative,
+	}}
+}
+
+func TestLinkPending(t *testing.T) {
+	foreign := pendingCard("2018-10-01", "105.00", "AMAZON")
+	foreign.Data().OriginalCurrency = "EUR"
+	pending := []Transaction{
+		pendingCard("2018-10-01", "59.90", "ICA"),
+		foreign,
+		pendingCard("2018-10-01", "20.00", "SL"),
+	}
+	booked := []Transaction{
+		bookedCard("2018-10-03", "102.37", "AMAZON MKTPLACE"),
+		bookedCard("2018-10-02", "59.90", "ICA MAXI"),
+		bookedCard("2018-10-25", "20.00", "SL"),
+	}
+
+	links, unlinked := LinkPending(pending, booked, LinkOptions{})
+	if len(links) != 2 {
+		t.Fatalf("LinkPending was incorrect, got: %d links, want: 2.", len(links))
+	}
+	if links[0].Booked != booked[1] || links[1].Booked != booked[0] {
+		t.Errorf("LinkPending was incorrect, got: %v, want: ICA and AMAZON linked.", links)
+	}
+	if len(unlinked) != 1 || unlinked[0] != pending[2] {
+		t.Errorf("LinkPending was incorrect, got: %v unlinked, want: SL booked after the maximum number of days.", unlinked)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	transactions := []Transaction{
+		pendingCard("2018-10-01", "59.90", "ICA"),
+		bookedCard("2018-10-02", "59.90", "ICA"),
+		pendingCard("2018-10-02", "35.00", "Pressbyrån"),
+	}
+
+	settled := Settle(transactions, LinkOptions{})
+	if len(settled) != 2 || settled[0] != transactions[1] || settled[1] != transactions[2] {
+		t.Errorf("Settle was incorrect, got: %v, want: the booked ICA and the pending Pressbyrån transactions.", settled)
+	}
+}
+
+func TestLinkPendingChangedAmount(t *testing.T) {
+	// The tip is added to the restaurant bill when the card payment is booked
+	pending := []Transaction{pendingCard("2018-10-05", "100.00", "RESTAURANG PELIKAN")}
+	booked := []Transaction{bookedCard("2018-10-08", "112.00", "RESTAURANG PELIKAN")}
+
+	links, _ := LinkPending(pending, booked, LinkOptions{})
+	if len(links) != 1 || links[0].Booked != booked[0] {
+		t.Errorf("LinkPending was incorrect, got: %v, want: 100.00 linked to 112.00.", links)
+	}
+
+	links, unlinked := LinkPending(pending, booked, LinkOptions{AmountTolerance: nordeago.MustParseDecimal("0.05")})
+	if len(links) != 0 || len(unlinked) != 1 {
+		t.Errorf("LinkPending was incorrect, got: %v, want: no links outside the amount tolerance.", links)
+	}
+
+	// An exact amount is preferred over an amount within the tolerance
+	booked = append(booked, bookedCard("2018-10-08", "100.00", "RESTAURANG PELIKAN"))
+	links, _ = LinkPending(pending, booked, LinkOptions{})
+	if len(links) != 1 || links[0].Booked != booked[1] {
+		t.Errorf("LinkPending was incorrect, got: %v, want: the exact amount linked.", links)
+	}
+}
@@ -0,0 +1,348 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package recurring finds subscriptions and other recurring debits in the transaction history of an account and
+// reports when an expected payment is missed or its price changes
+package recurring
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+	"github.com/markustenghamn/nordeago/ais/merchant"
+)
+
+// Frequency is the interval between the payments of a subscription
+type Frequency string
+
+// Frequencies detected by Detect
+const (
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+	Yearly  Frequency = "yearly"
+)
+
+// frequencyRule contains the number of days allowed between payments of a frequency, including the days payments
+// move because of weekends and holidays, the average length of the period in days and the grace period before a
+// payment is reported as missed
+type frequencyRule struct {
+	frequency      Frequency
+	minDays        int
+	maxDays        int
+	periodDays     float64
+	graceDays      int
+	minOccurrences int
+}
+
+var frequencyRules = []frequencyRule{
+	{Weekly, 5, 9, 7, 3, 3},
+	{Monthly, 26, 35, 365.25 / 12, 5, 3},
+	{Yearly, 350, 380, 365.25, 14, 2},
+}
+
+// periods returns the number of periods in the interval or 0 if the interval does not match the frequency. An
+// interval of several periods is a payment that was missed or not part of the transactions.
+func (r frequencyRule) periods(days int) int {
+	periods := int(math.Round(float64(days) / r.periodDays))
+	if periods < 1 {
+		return 0
+	}
+	extra := int(math.Round(float64(periods-1) * r.periodDays))
+	if days < r.minDays+extra || days > r.maxDays+extra {
+		return 0
+	}
+	return periods
+}
+
+// next returns the date of the payment following date. Monthly and yearly payments are due on the day of the month
+// the subscription started, or on the last day of the month when the month is shorter.
+func (f Frequency) next(date nordeago.Date, day int) nordeago.Date {
+	switch f {
+	case Monthly:
+		return dateInMonth(date.Year(), date.Month()+1, day)
+	case Yearly:
+		return dateInMonth(date.Year()+1, date.Month(), day)
+	default:
+		return date.AddDays(7)
+	}
+}
+
+// dateInMonth returns the day of the month, or the last day of the month if it has fewer days. The month is
+// normalized the same way as time.Date.
+func dateInMonth(year int, month time.Month, day int) nordeago.Date {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return nordeago.NewDate(first.Year(), first.Month(), day)
+}
+
+// DefaultAmountTolerance is the default relative difference between amounts of the same subscription
+var DefaultAmountTolerance = nordeago.MustParseDecimal("0.10")
+
+// Options configures Detect
+type Options struct {
+	// Normalizer is used for transactions without a normalized counterparty, defaults to merchant.DefaultNormalizer
+	Normalizer *merchant.Normalizer
+	// AmountTolerance is the relative amount difference between payments that is not a price change, defaults to
+	// DefaultAmountTolerance
+	AmountTolerance nordeago.Decimal
+	// MinOccurrences overrides the number of payments needed to detect a subscription, which is 3 for weekly and
+	// monthly payments and 2 for yearly payments
+	MinOccurrences int
+	// AsOf is the date used to find missed payments, defaults to today in Stockholm
+	AsOf nordeago.Date
+}
+
+// Subscription is a recurring debit to the same counterparty
+type Subscription struct {
+	Merchant  string
+	Frequency Frequency
+	// Amount is the amount of the latest payment as a positive value
+	Amount       nordeago.Money
+	FirstDate    nordeago.Date
+	LastDate     nordeago.Date
+	NextDate     nordeago.Date
+	Occurrences  int
+	Transactions []ais.Transaction
+}
+
+// AlertType describes what happened to a subscription
+type AlertType string
+
+// Alerts reported by Detect
+const (
+	// Missed is reported when the expected payment date plus a grace period has passed without a payment
+	Missed AlertType = "missed"
+	// PriceChanged is reported when a payment differs from the one before it by more than the tolerance. The alert
+	// is reported for as long as the change is part of the transactions, use Date to tell new changes from old ones.
+	PriceChanged AlertType = "priceChanged"
+)
+
+// Alert is a change to a subscription. Previous is the amount before a price change. Date is the first payment at the
+// new price for a price change and the expected payment date for a missed payment.
+type Alert struct {
+	Type         AlertType
+	Subscription Subscription
+	Previous     nordeago.Money
+	Date         nordeago.Date
+	Message      string
+}
+
+// Result contains the subscriptions sorted by merchant and the alerts for them
+type Result struct {
+	Subscriptions []Subscription
+	Alerts        []Alert
+}
+
+// Detect finds recurring booked debits in the transactions. Debits are grouped by normalized counterparty and a group
+// is a subscription when the payments have a weekly, monthly or yearly interval, where a missed payment is allowed, and
+// similar amounts. Every price change is reported, but a payment at a new price directly followed by another price
+// change means that the amounts vary and the payments are not a subscription.
+func Detect(transactions []ais.Transaction, options Options) Result {
+	normalizer := options.Normalizer
+	if normalizer == nil {
+		normalizer = merchant.DefaultNormalizer()
+	}
+	tolerance := options.AmountTolerance
+	if !tolerance.IsSet() {
+		tolerance = DefaultAmountTolerance
+	}
+	asOf := options.AsOf
+	if asOf.IsZero() {
+		asOf = nordeago.DateOf(time.Now(), nordeago.Stockholm)
+	}
+
+	groups := make(map[string][]ais.Transaction)
+	names := make(map[string]string)
+	for _, t := range transactions {
+		data := t.Data()
+		if t.TransactionType() != ais.TransactionTypeDebit || data.Status.IsPending() || paymentDate(t).IsZero() {
+			continue
+		}
+		name := data.NormalizedCounterparty
+		if len(name) == 0 {
+			name = normalizer.NormalizeTransaction(t)
+		}
+		if len(name) == 0 {
+			continue
+		}
+		key := strings.ToLower(name) + "\x00" + strings.ToUpper(data.Currency)
+		if _, ok := names[key]; !ok {
+			names[key] = name
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var result Result
+	for _, key := range keys {
+		subscription, changes, ok := detectSubscription(names[key], groups[key], tolerance, options.MinOccurrences)
+		if !ok {
+			continue
+		}
+		result.Subscriptions = append(result.Subscriptions, subscription)
+
+		for _, change := range changes {
+			before, after := subscription.Transactions[change-1], subscription.Transactions[change]
+			previous := nordeago.NewMoney(amount(before), before.Data().Currency)
+			current := nordeago.NewMoney(amount(after), after.Data().Currency)
+			result.Alerts = append(result.Alerts, Alert{
+				Type:         PriceChanged,
+				Subscription: subscription,
+				Previous:     previous,
+				Date:         paymentDate(after),
+				Message:      fmt.Sprintf("%s changed price from %s to %s on %s", subscription.Merchant, previous, current, paymentDate(after)),
+			})
+		}
+		if rule := ruleFor(subscription.Frequency); subscription.NextDate.AddDays(rule.graceDays).Before(asOf) {
+			result.Alerts = append(result.Alerts, Alert{
+				Type:         Missed,
+				Subscription: subscription,
+				Date:         subscription.NextDate,
+				Message:      fmt.Sprintf("%s was expected on %s", subscription.Merchant, subscription.NextDate),
+			})
+		}
+	}
+	return result
+}
+
+// detectSubscription checks the intervals and amounts of the payments to a counterparty. The indexes of the first
+// payment at each new price in the transactions of the subscription are returned.
+func detectSubscription(name string, payments []ais.Transaction, tolerance nordeago.Decimal, minOccurrences int) (Subscription, []int, bool) {
+	slices.SortStableFunc(payments, func(a, b ais.Transaction) int {
+		return paymentDate(a).Compare(paymentDate(b))
+	})
+
+	// Payments on the same day are counted once, for example a charge and its correction
+	var dates []nordeago.Date
+	var unique []ais.Transaction
+	for _, t := range payments {
+		date := paymentDate(t)
+		if len(dates) > 0 && dates[len(dates)-1].Equal(date) {
+			continue
+		}
+		dates = append(dates, date)
+		unique = append(unique, t)
+	}
+	if len(unique) < 2 {
+		return Subscription{}, nil, false
+	}
+
+	var intervals []int
+	for i := 1; i < len(dates); i++ {
+		intervals = append(intervals, daysBetween(dates[i-1], dates[i]))
+	}
+
+	// Every interval must be one or more periods and at least one of them a single period, otherwise a yearly
+	// payment would also be a weekly one where all other payments were missed
+	var rule *frequencyRule
+	for i := range frequencyRules {
+		r := &frequencyRules[i]
+		if !slices.ContainsFunc(intervals, func(days int) bool { return r.periods(days) == 0 }) &&
+			slices.ContainsFunc(intervals, func(days int) bool { return r.periods(days) == 1 }) {
+			rule = r
+			break
+		}
+	}
+	if rule == nil {
+		return Subscription{}, nil, false
+	}
+	required := rule.minOccurrences
+	if minOccurrences > 0 {
+		required = minOccurrences
+	}
+	if len(unique) < required {
+		return Subscription{}, nil, false
+	}
+
+	// A price change right after another one means that the amounts vary, such as purchases in the same store
+	var changes []int
+	for i := 1; i < len(unique); i++ {
+		if similar(amount(unique[i-1]), amount(unique[i]), tolerance) {
+			continue
+		}
+		if len(changes) > 0 && changes[len(changes)-1] == i-1 {
+			return Subscription{}, nil, false
+		}
+		changes = append(changes, i)
+	}
+
+	latest := unique[len(unique)-1]
+	subscription := Subscription{
+		Merchant:     name,
+		Frequency:    rule.frequency,
+		Amount:       nordeago.NewMoney(amount(latest), latest.Data().Currency),
+		FirstDate:    dates[0],
+		LastDate:     dates[len(dates)-1],
+		NextDate:     rule.frequency.next(dates[len(dates)-1], dates[0].Day()),
+		Occurrences:  len(unique),
+		Transactions: unique,
+	}
+	return subscription, changes, true
+}
+
+func ruleFor(frequency Frequency) frequencyRule {
+	for _, rule := range frequencyRules {
+		if rule.frequency == frequency {
+			return rule
+		}
+	}
+	return frequencyRule{}
+}
+
+func paymentDate(t ais.Transaction) nordeago.Date {
+	data := t.Data()
+	for _, date := range []nordeago.Date{data.BookingDate, data.TransactionDate, data.ValueDate} {
+		if !date.IsZero() {
+			return date
+		}
+	}
+	return nordeago.Date{}
+}
+
+func amount(t ais.Transaction) nordeago.Decimal {
+	return t.Data().Amount.Abs()
+}
+
+// similar returns true if the difference between a and b is at most tolerance times the larger amount
+func similar(a nordeago.Decimal, b nordeago.Decimal, tolerance nordeago.Decimal) bool {
+	larger := a
+	if b.Cmp(a) > 0 {
+		larger = b
+	}
+	return a.Sub(b).Abs().Cmp(larger.Mul(tolerance)) <= 0
+}
+
+func daysBetween(a nordeago.Date, b nordeago.Date) int {
+	return int(b.In(time.UTC).Sub(a.In(time.UTC)).Hours() / 24)
+}
@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package recurring
+
+import (
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func payment(date string, narrative string, amount string) ais.Transaction {
+	return &ais.DebitTransaction{TransactionData: ais.TransactionData{
+		Status:      ais.TransactionStatusBooked,
+		BookingDate: nordeago.MustParseDate(date),
+		Narrative:   narrative,
+		Amount:      nordeago.MustParseDecimal(amount),
+		Currency:    "SEK",
+	}}
+}
+
+func history() []ais.Transaction {
+	return []ais.Transaction{
+		payment("2018-07-03", "KORTKÖP 180703 SPOTIFY STOCKHOLM", "99.00"),
+		payment("2018-08-03", "KORTKÖP 180803 SPOTIFY STOCKHOLM", "99.00"),
+		payment("2018-09-04", "KORTKÖP 180904 SPOTIFY STOCKHOLM", "99.00"),
+		payment("2018-10-03", "KORTKÖP 181003 SPOTIFY STOCKHOLM", "119.00"),
+		payment("2018-07-28", "AUTOGIRO TELIA", "399.00"),
+		payment("2018-08-28", "AUTOGIRO TELIA", "399.00"),
+		payment("2018-09-28", "AUTOGIRO TELIA", "405.00"),
+		payment("2018-09-08", "KORTKÖP 180908 ICA NARA ST", "59.90"),
+		payment("2018-09-15", "KORTKÖP 180915 ICA NARA ST", "312.40"),
+		payment("2018-09-22", "KORTKÖP 180922 ICA NARA ST", "145.00"),
+		payment("2018-09-29", "KORTKÖP 180929 ICA NARA ST", "98.50"),
+		payment("2018-09-03", "Sats GYM", "449.00"),
+		payment("2018-09-10", "Sats GYM", "449.00"),
+		payment("2018-09-17", "Sats GYM", "449.00"),
+		payment("2017-10-01", "FÖRSÄKRING IF", "1200.00"),
+		payment("2018-10-01", "FÖRSÄKRING IF", "1250.00"),
+	}
+}
+
+func TestDetect(t *testing.T) {
+	result := Detect(history(), Options{AsOf: nordeago.MustParseDate("2018-10-05")})
+
+	want := map[string]struct {
+		frequency Frequency
+		amount    string
+		next      string
+	}{
+		"Spotify":       {Monthly, "119.00 SEK", "2018-11-03"},
+		"Telia":         {Monthly, "405.00 SEK", "2018-10-28"},
+		"Sats GYM":      {Weekly, "449.00 SEK", "2018-09-24"},
+		"Försäkring IF": {Yearly, "1250.00 SEK", "2019-10-01"},
+	}
+	if len(result.Subscriptions) != len(want) {
+		t.Fatalf("Detect was incorrect, got: %d subscriptions %+v, want: %d.", len(result.Subscriptions), result.Subscriptions, len(want))
+	}
+	for _, s := range result.Subscriptions {
+		w, ok := want[s.Merchant]
+		if !ok {
+			t.Errorf("Detect was incorrect, got: unexpected subscription %s.", s.Merchant)
+			continue
+		}
+		if s.Frequency != w.frequency || s.Amount.String() != w.amount || s.NextDate.String() != w.next {
+			t.Errorf("subscription %s was incorrect, got: %s %s next %s, want: %s %s next %s.", s.Merchant, s.Frequency, s.Amount, s.NextDate, w.frequency, w.amount, w.next)
+		}
+	}
+
+	alerts := make(map[string]AlertType)
+	for _, alert := range result.Alerts {
+		alerts[alert.Subscription.Merchant] = alert.Type
+		if alert.Type == PriceChanged && alert.Subscription.Merchant == "Spotify" && alert.Previous.String() != "99.00 SEK" {
+			t.Errorf("previous price was incorrect, got: %s, want: 99.00 SEK.", alert.Previous)
+		}
+	}
+	wantAlerts := map[string]AlertType{"Spotify": PriceChanged, "Sats GYM": Missed}
+	if len(alerts) != len(wantAlerts) || len(result.Alerts) != len(wantAlerts) {
+		t.Errorf("alerts were incorrect, got: %v, want: %v.", alerts, wantAlerts)
+	}
+	for merchant, alertType := range wantAlerts {
+		if alerts[merchant] != alertType {
+			t.Errorf("alert for %s was incorrect, got: %s, want: %s.", merchant, alerts[merchant], alertType)
+		}
+	}
+}
+
+func TestDetectUsesNormalizedCounterparty(t *testing.T) {
+	transactions := []ais.Transaction{
+		payment("2018-08-01", "Netflix.com", "109"),
+		payment("2018-09-01", "NETFLIX INTERNATIONAL", "109"),
+		payment("2018-10-01", "Netflix", "109"),
+	}
+	for _, transaction := range transactions {
+		transaction.Data().NormalizedCounterparty = "Netflix"
+	}
+
+	result := Detect(transactions, Options{AsOf: nordeago.MustParseDate("2018-10-02")})
+	if len(result.Subscriptions) != 1 || result.Subscriptions[0].Occurrences != 3 || len(result.Alerts) != 0 {
+		t.Errorf("Detect was incorrect, got: %+v, want: one Netflix subscription without alerts.", result)
+	}
+}
+
+func TestDetectMonthEnd(t *testing.T) {
+	tests := []struct {
+		dates []string
+		next  string
+	}{
+		{[]string{"2024-11-30", "2024-12-31", "2025-01-31"}, "2025-02-28"},
+		{[]string{"2024-12-31", "2025-01-31", "2025-02-28"}, "2025-03-31"},
+		{[]string{"2020-02-29", "2021-02-28", "2022-02-28"}, "2023-02-28"},
+	}
+	for _, test := range tests {
+		var transactions []ais.Transaction
+		for _, date := range test.dates {
+			transactions = append(transactions, payment(date, "AUTOGIRO HYRA", "8500.00"))
+		}
+		result := Detect(transactions, Options{AsOf: nordeago.MustParseDate(test.dates[len(test.dates)-1])})
+		if len(result.Subscriptions) != 1 || result.Subscriptions[0].NextDate.String() != test.next {
+			t.Errorf("NextDate after %v was incorrect, got: %+v, want: %s.", test.dates, result.Subscriptions, test.next)
+		}
+	}
+
+	// The grace period starts from the clamped date
+	transactions := []ais.Transaction{
+		payment("2024-11-30", "AUTOGIRO HYRA", "8500.00"),
+		payment("2024-12-31", "AUTOGIRO HYRA", "8500.00"),
+		payment("2025-01-31", "AUTOGIRO HYRA", "8500.00"),
+	}
+	if result := Detect(transactions, Options{AsOf: nordeago.MustParseDate("2025-03-06")}); len(result.Alerts) != 1 || result.Alerts[0].Type != Missed {
+		t.Errorf("Missed alert was incorrect, got: %+v, want: one missed alert.", result.Alerts)
+	}
+}
+
+func TestDetectEarlierPriceChange(t *testing.T) {
+	transactions := []ais.Transaction{
+		payment("2018-06-12", "NETFLIX.COM", "109.00"),
+		payment("2018-07-12", "NETFLIX.COM", "109.00"),
+		payment("2018-08-13", "NETFLIX.COM", "139.00"),
+		payment("2018-09-12", "NETFLIX.COM", "139.00"),
+	}
+
+	result := Detect(transactions, Options{AsOf: nordeago.MustParseDate("2018-09-20")})
+	if len(result.Alerts) != 1 {
+		t.Fatalf("Detect was incorrect, got: %+v, want: one price change alert.", result.Alerts)
+	}
+	alert := result.Alerts[0]
+	if alert.Type != PriceChanged || alert.Previous.String() != "109.00 SEK" || alert.Subscription.Amount.String() != "139.00 SEK" || alert.Date.String() != "2018-08-13" {
+		t.Errorf("price change was incorrect, got: %s from %s to %s on %s, want: priceChanged from 109.00 SEK to 139.00 SEK on 2018-08-13.", alert.Type, alert.Previous, alert.Subscription.Amount, alert.Date)
+	}
+}
+
+func TestDetectMissedPayment(t *testing.T) {
+	transactions := []ais.Transaction{
+		payment("2018-05-25", "AUTOGIRO CMORE", "399.00"),
+		payment("2018-06-25", "AUTOGIRO CMORE", "399.00"),
+		payment("2018-08-27", "AUTOGIRO CMORE", "399.00"),
+		payment("2018-09-25", "AUTOGIRO CMORE", "399.00"),
+	}
+
+	result := Detect(transactions, Options{AsOf: nordeago.MustParseDate("2018-10-01")})
+	if len(result.Subscriptions) != 1 {
+		t.Fatalf("Detect was incorrect, got: %+v, want: one subscription with a missed month.", result.Subscriptions)
+	}
+	s := result.Subscriptions[0]
+	if s.Frequency != Monthly || s.Occurrences != 4 || s.NextDate.String() != "2018-10-25" || len(result.Alerts) != 0 {
+		t.Errorf("subscription was incorrect, got: %s %d payments next %s alerts %+v, want: monthly 4 payments next 2018-10-25 without alerts.", s.Frequency, s.Occurrences, s.NextDate, result.Alerts)
+	}
+}
+
+func TestDetectPriceChanges(t *testing.T) {
+	transactions := []ais.Transaction{
+		payment("2018-04-12", "NETFLIX.COM", "109.00"),
+		payment("2018-05-14", "NETFLIX.COM", "109.00"),
+		payment("2018-06-12", "NETFLIX.COM", "139.00"),
+		payment("2018-07-12", "NETFLIX.COM", "139.00"),
+		payment("2018-08-13", "NETFLIX.COM", "159.00"),
+		payment("2018-09-12", "NETFLIX.COM", "159.00"),
+	}
+
+	result := Detect(transactions, Options{AsOf: nordeago.MustParseDate("2018-09-20")})
+	want := []struct {
+		previous string
+		date     string
+	}{
+		{"109.00 SEK", "2018-06-12"},
+		{"139.00 SEK", "2018-08-13"},
+	}
+	if len(result.Alerts) != len(want) {
+		t.Fatalf("Detect was incorrect, got: %+v, want: %d price change alerts.", result.Alerts, len(want))
+	}
+	for i, alert := range result.Alerts {
+		if alert.Type != PriceChanged || alert.Previous.String() != want[i].previous || alert.Date.String() != want[i].date {
+			t.Errorf("price change was incorrect, got: %s from %s on %s, want: priceChanged from %s on %s.", alert.Type, alert.Previous, alert.Date, want[i].previous, want[i].date)
+		}
+	}
+
+	// Amounts that change with every payment are not a subscription
+	transactions = []ais.Transaction{
+		payment("2018-06-12", "NETFLIX.COM", "109.00"),
+		payment("2018-07-12", "NETFLIX.COM", "139.00"),
+		payment("2018-08-13", "NETFLIX.COM", "159.00"),
+		payment("2018-09-12", "NETFLIX.COM", "189.00"),
+	}
+	if result := Detect(transactions, Options{AsOf: nordeago.MustParseDate("2018-09-20")}); len(result.Subscriptions) != 0 {
+		t.Errorf("Detect was incorrect, got: %+v, want: no subscriptions.", result.Subscriptions)
+	}
+}
@@ -1,27 +1,32 @@
 package ais
 
-import "github.com/markustenghamn/nordeago"
+import (
+	"encoding/json"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/iban"
+)
 
 // CreateAccountRequest is used with the CreateAccount method to create an account
 type CreateAccountRequest struct {
-	ID                           string          `json:"_id"`
-	Links                        []nordeago.Link `json:"_links"`
-	AccountName                  string          `json:"accountName"`
-	AccountNumber                AccountNumber   `json:"accountNumber"`
-	AccountNumbers               []AccountNumber `json:"accountNumbers"`
-	AccountType                  string          `json:"accountType"` // Always 'Current'
-	AvailableBalance             string          `json:"availableBalance"`
-	Bank                         Bank            `json:"bank"`
-	BookedBalance                string          `json:"bookedBalance"`
-	Country                      string          `json:"country,omitempty"`
-	Created                      string          `json:"created"`
-	CreditLimit                  string          `json:"creditLimit,omitempty"`
-	Currency                     string          `json:"currency"` // Currency code according to ISO 4217
-	LatestTransactionBookingDate string          `json:"latestTransactionBookingDate,omitempty"`
-	OwnerName                    string          `json:"ownerName"`
-	Product                      string          `json:"product"`
-	Status                       string          `json:"status"` // OPEN or CLOSED
-	ValueDatedBalance            string          `json:"valueDatedBalance,omitempty"`
+	ID                           string           `json:"_id"`
+	Links                        []nordeago.Link  `json:"_links"`
+	AccountName                  string           `json:"accountName"`
+	AccountNumber                AccountNumber    `json:"accountNumber"`
+	AccountNumbers               []AccountNumber  `json:"accountNumbers"`
+	AccountType                  string           `json:"accountType"` // Always 'Current'
+	AvailableBalance             nordeago.Decimal `json:"availableBalance"`
+	Bank                         Bank             `json:"bank"`
+	BookedBalance                nordeago.Decimal `json:"bookedBalance"`
+	Country                      string           `json:"country,omitempty"`
+	Created                      nordeago.Date    `json:"created"`
+	CreditLimit                  nordeago.Decimal `json:"creditLimit,omitzero"`
+	Currency                     string           `json:"currency"` // Currency code according to ISO 4217
+	LatestTransactionBookingDate nordeago.Date    `json:"latestTransactionBookingDate,omitzero"`
+	OwnerName                    string           `json:"ownerName"`
+	Product                      string           `json:"product"`
+	Status                       string           `json:"status"` // OPEN or CLOSED
+	ValueDatedBalance            nordeago.Decimal `json:"valueDatedBalance,omitzero"`
 }
 
 // AccountNumber represents an account number
@@ -30,34 +35,62 @@ type AccountNumber struct {
 	Value string `json:"value,omitempty"`
 }
 
-// GetAccountTransactionsRequest is used with the GetAccountTransactions method to list transactions for the specified account id
-type GetAccountTransactionsRequest struct {
-	FromDate        string `json:"fromDate"`
-	ToDate          string `json:"toDate"`
-	Language        string `json:"language"`
-	ContinuationKey string `json:"continuationKey"`
-}
-
-// Transaction is used to create or return a transaction
-type Transaction struct {
-	Type                    string `json:"_type"` // CreditTransaction or DebitTransaction
-	Amount                  string `json:"amount,omitempty"`
-	BalanceAfterTransaction string `json:"balanceAfterTransaction,omitempty"`
-	BookingDate             string `json:"bookingDate"`
-	CardNumber              string `json:"cardNumber,omitempty"`
-	CounterpartyName        string `json:"counterpartName,omitempty"`
-	Currency                string `json:"currency"`
-	CurrencyRate            string `json:"currencyRate,omitempty"`
-	Message                 string `json:"message,omitempty"`
-	Narrative               string `json:"narrative,omitempty"`
-	OriginalCurrency        string `json:"originalCurrency,omitempty"`
-	OriginalCurrencyAmount  string `json:"originalCurrencyAmount,omitempty"`
-	OwnMessage              string `json:"ownMessage,omitempty"`
-	PaymentDate             string `json:"paymentDate,omitempty"`
-	Reference               string `json:"reference,omitempty"`
-	Status                  string `json:"status"`
-	TransactionDate         string `json:"transactionDate,omitempty"`
-	TransactionID           string `json:"transactionId"`
-	TypeDescription         string `json:"typeDescription,omitempty"`
-	ValueDate               string `json:"valueDate,omitempty"`
+// Validate validates the account number according to its type, see iban.ValidateAccount
+func (a AccountNumber) Validate() error {
+	return iban.ValidateAccount(a.Type, a.Value)
+}
+
+// Transaction is used to create or return a transaction, it is a CreditTransaction or a DebitTransaction depending on
+// the _type returned by the API, or an UnknownTransaction for types this package does not know about
+type Transaction interface {
+	// Data returns the fields shared by all transaction types
+	Data() *TransactionData
+	// TransactionType returns the _type of the transaction
+	TransactionType() string
+	// SignedAmount returns the amount in the currency of the transaction, negative for debits and positive for credits
+	SignedAmount() nordeago.Money
+}
+
+// CreditTransaction is a transaction where money was paid into the account
+type CreditTransaction struct {
+	TransactionData
+}
+
+// DebitTransaction is a transaction where money was paid from the account
+type DebitTransaction struct {
+	TransactionData
+}
+
+// UnknownTransaction is a transaction with a _type that is missing or not known by this package. Raw contains the
+// transaction as returned by the API.
+type UnknownTransaction struct {
+	TransactionData
+	Type string
+	Raw  json.RawMessage
+}
+
+// TransactionData contains the fields of a transaction, Amount is the amount as returned by the API which can be
+// signed or unsigned. Use SignedAmount on the Transaction to get an amount with the correct sign.
+type TransactionData struct {
+	Amount                  nordeago.Decimal  `json:"amount,omitzero"`
+	BalanceAfterTransaction nordeago.Decimal  `json:"balanceAfterTransaction,omitzero"`
+	BookingDate             nordeago.Date     `json:"bookingDate"`
+	CardNumber              string            `json:"cardNumber,omitempty"`
+	CounterpartyName        string            `json:"counterpartName,omitempty"`
+	Currency                string            `json:"currency"`
+	CurrencyRate            nordeago.Decimal  `json:"currencyRate,omitzero"`
+	Message                 string            `json:"message,omitempty"`
+	Narrative               string            `json:"narrative,omitempty"`
+	OriginalCurrency        string            `json:"originalCurrency,omitempty"`
+	OriginalCurrencyAmount  nordeago.Decimal  `json:"originalCurrencyAmount,omitzero"`
+	OwnMessage              string            `json:"ownMessage,omitempty"`
+	PaymentDate             nordeago.Date     `json:"paymentDate,omitzero"`
+	Reference               string            `json:"reference,omitempty"`
+	Status                  TransactionStatus `json:"status"`
+	TransactionDate         nordeago.Date     `json:"transactionDate,omitzero"`
+	TransactionID           string            `json:"transactionId"`
+	TypeDescription         string            `json:"typeDescription,omitempty"`
+	ValueDate               nordeago.Date     `json:"valueDate,omitzero"`
+	// NormalizedCounterparty is not returned by the API, it is filled by merchant.Normalizer
+	NormalizedCounterparty string `json:"normalizedCounterparty,omitempty"`
 }
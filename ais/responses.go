@@ -4,23 +4,23 @@ import "github.com/markustenghamn/nordeago"
 
 // AccountDetailed is returned as part of the ListAccountsResponse when fetching account details
 type AccountDetailed struct {
-	AccountName                  string          `json:"accountName"`
-	AccountNumber                AccountNumber   `json:"accountNumber,omitempty"`
-	AccountNumbers               []AccountNumber `json:"accountNumbers,omitempty"`
-	ID                           string          `json:"_id,omitempty"`
-	Links                        []nordeago.Link `json:"_links,omitempty"`
-	AccountType                  string          `json:"accountType"` // Always Current
-	AvailableBalance             string          `json:"availableBalance"`
-	Bank                         Bank            `json:"bank"`
-	BookedBalance                string          `json:"bookedBalance"`
-	Country                      string          `json:"country,omitempty"`
-	CreditLimit                  string          `json:"creditLimit,omitempty"`
-	Currency                     string          `json:"currency"`
-	LatestTransactionBookingDate string          `json:"latestTransactionBookingDate,omitempty"`
-	OwnerName                    string          `json:"ownerName,omitempty"`
-	Product                      string          `json:"product"`
-	Status                       string          `json:"status"`
-	ValueDatedBalance            string          `json:"valueDatedBalance,omitempty"`
+	AccountName                  string           `json:"accountName"`
+	AccountNumber                AccountNumber    `json:"accountNumber,omitempty"`
+	AccountNumbers               []AccountNumber  `json:"accountNumbers,omitempty"`
+	ID                           string           `json:"_id,omitempty"`
+	Links                        []nordeago.Link  `json:"_links,omitempty"`
+	AccountType                  string           `json:"accountType"` // Always Current
+	AvailableBalance             nordeago.Decimal `json:"availableBalance"`
+	Bank                         Bank             `json:"bank"`
+	BookedBalance                nordeago.Decimal `json:"bookedBalance"`
+	Country                      string           `json:"country,omitempty"`
+	CreditLimit                  nordeago.Decimal `json:"creditLimit,omitzero"`
+	Currency                     string           `json:"currency"`
+	LatestTransactionBookingDate nordeago.Date    `json:"latestTransactionBookingDate,omitzero"`
+	OwnerName                    string           `json:"ownerName,omitempty"`
+	Product                      string           `json:"product"`
+	Status                       string           `json:"status"`
+	ValueDatedBalance            nordeago.Decimal `json:"valueDatedBalance,omitzero"`
 }
 
 // Bank represents a bank entity in request and response types
@@ -42,7 +42,7 @@ type Account struct {
 type GetAccountTransactionsResponse struct {
 	ContinuationKey string          `json:"continuationKey"`
 	Links           []nordeago.Link `json:"links"`
-	Transactions    []Transaction   `json:"transactions"`
+	Transactions    TransactionList `json:"transactions"`
 }
 
 // ListAccountsResponse contains a list of AccountDetailed types
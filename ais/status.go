@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// TransactionStatus is the status of a transaction
+type TransactionStatus string
+
+// Transaction statuses returned by the API and accepted by TransactionFilter
+const (
+	TransactionStatusBooked  TransactionStatus = "booked"
+	TransactionStatusPending TransactionStatus = "pending"
+)
+
+// IsBooked returns true if the transaction has been booked on the account
+func (s TransactionStatus) IsBooked() bool {
+	return s == TransactionStatusBooked
+}
+
+// IsPending returns true if the transaction is reserved but not yet booked. A pending transaction is replaced by a
+// booked transaction, often with another transaction id, see LinkPending.
+func (s TransactionStatus) IsPending() bool {
+	return s == TransactionStatusPending
+}
+
+// UnmarshalJSON decodes the status, known statuses are matched regardless of case
+func (s *TransactionStatus) UnmarshalJSON(data []byte) error {
+	var status string
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+
+	*s = TransactionStatus(status)
+	for _, known := range []TransactionStatus{TransactionStatusBooked, TransactionStatusPending} {
+		if strings.EqualFold(status, string(known)) {
+			*s = known
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package sync keeps a local copy of account transactions up to date by polling ais.GetAccountTransactions. Only the
+// transactions booked since the last sync plus a safety overlap are fetched and the differences are emitted as events.
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	stdsync "sync"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// ErrCursorNotFound is returned by a CursorStore when no cursor has been saved for the account
+var ErrCursorNotFound = errors.New("cursor not found")
+
+// Cursor is the sync state of an account
+type Cursor struct {
+	AccountID string `json:"accountId"`
+	// LastBookingDate is the latest booking date of the booked transactions seen so far
+	LastBookingDate nordeago.Date `json:"lastBookingDate"`
+	// Seen contains the transactions inside the overlap window and all pending transactions by transaction id
+	Seen map[string]SeenTransaction `json:"seen,omitempty"`
+}
+
+// SeenTransaction is what the cursor remembers about a transaction to detect changes
+type SeenTransaction struct {
+	Status      ais.TransactionStatus `json:"status"`
+	BookingDate nordeago.Date         `json:"bookingDate"`
+	Hash        string                `json:"hash"`
+	// Transaction is the pending transaction, used to link it to the booked transaction that replaces it
+	Transaction json.RawMessage `json:"transaction,omitempty"`
+}
+
+// CursorStore persists cursors between syncs
+type CursorStore interface {
+	Load(accountID string) (Cursor, error)
+	Save(accountID string, cursor Cursor) error
+}
+
+// clone returns a copy of the cursor that does not share the Seen map
+func (c Cursor) clone() Cursor {
+	if c.Seen != nil {
+		c.Seen = maps.Clone(c.Seen)
+	}
+	return c
+}
+
+// MemoryCursorStore is a CursorStore that keeps cursors in memory. The zero value is ready to use.
+type MemoryCursorStore struct {
+	mu      stdsync.RWMutex
+	cursors map[string]Cursor
+}
+
+// Load returns a copy of the cursor for the account or ErrCursorNotFound
+func (s *MemoryCursorStore) Load(accountID string) (Cursor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cursor, ok := s.cursors[accountID]
+	if !ok {
+		return cursor, ErrCursorNotFound
+	}
+	return cursor.clone(), nil
+}
+
+// Save stores a copy of the cursor for the account
+func (s *MemoryCursorStore) Save(accountID string, cursor Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursors == nil {
+		s.cursors = make(map[string]Cursor)
+	}
+	s.cursors[accountID] = cursor.clone()
+	return nil
+}
+
+// FileCursorStore is a CursorStore that saves each cursor as a JSON file in a directory
+type FileCursorStore struct {
+	Dir string
+}
+
+// Load reads the cursor for the account or returns ErrCursorNotFound if there is no file
+func (s FileCursorStore) Load(accountID string) (Cursor, error) {
+	var cursor Cursor
+	data, err := os.ReadFile(s.path(accountID))
+	if errors.Is(err, os.ErrNotExist) {
+		return cursor, ErrCursorNotFound
+	}
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// Save writes the cursor to a temporary file which is then renamed so that a crash never leaves a partial cursor
+func (s FileCursorStore) Save(accountID string, cursor Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, ".cursor-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(accountID))
+}
+
+func (s FileCursorStore) path(accountID string) string {
+	return filepath.Join(s.Dir, url.PathEscape(accountID)+".json")
+}
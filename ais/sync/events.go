@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sync
+
+import "github.com/markustenghamn/nordeago/ais"
+
+// EventType describes how a transaction changed since the last sync
+type EventType int
+
+const (
+	// Added is emitted for a transaction that has not been seen before
+	Added EventType = iota + 1
+	// Updated is emitted when a seen transaction is returned with different content
+	Updated
+	// PendingBooked is emitted when a pending transaction is returned as booked, or when a booked transaction with
+	// another transaction id replaced it. ReplacesID of the event is the id of the pending transaction.
+	PendingBooked
+	// Removed is emitted when a seen transaction is no longer returned for a date that was fetched again, usually a
+	// pending transaction that was cancelled
+	Removed
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case Added:
+		return "Added"
+	case Updated:
+		return "Updated"
+	case PendingBooked:
+		return "PendingBooked"
+	case Removed:
+		return "Removed"
+	}
+	return "Unknown"
+}
+
+// Event is a change to a transaction of an account. Transaction is nil for Removed events.
+type Event struct {
+	Type          EventType
+	AccountID     string
+	TransactionID string
+	Transaction   ais.Transaction
+	// ReplacesID is the id of the pending transaction replaced by a PendingBooked event
+	ReplacesID string
+}
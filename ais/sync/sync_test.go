@@ -0,0 +1,216 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func testClient(server *httptest.Server) *nordeago.Client {
+	c := nordeago.InitClient("client-id", "client-secret", "https://httpbin.org/get")
+	c.Protocol = "http://"
+	c.BaseURL = strings.TrimPrefix(server.URL, "http://")
+	c.AccessToken = "token"
+	return &c
+}
+
+func collect(t *testing.T, s *Syncer, accountID string) []Event {
+	events := make(chan Event, 16)
+	if err := s.Sync(context.Background(), events, accountID); err != nil {
+		t.Fatal(err)
+	}
+	close(events)
+	var result []Event
+	for event := range events {
+		result = append(result, event)
+	}
+	return result
+}
+
+func TestSyncEmitsChanges(t *testing.T) {
+	responses := []string{
+		`[{"_type":"DebitTransaction","transactionId":"p1","status":"pending","amount":"10.00"},
+		  {"_type":"DebitTransaction","transactionId":"p2","status":"pending","amount":"20.00"},
+		  {"_type":"CreditTransaction","transactionId":"b1","status":"booked","bookingDate":"2018-10-02","amount":"100.00"}]`,
+		`[{"_type":"DebitTransaction","transactionId":"p1","status":"booked","bookingDate":"2018-10-05","amount":"10.00"},
+		  {"_type":"CreditTransaction","transactionId":"b1","status":"booked","bookingDate":"2018-10-02","amount":"100.00","message":"salary"},
+		  {"_type":"CreditTransaction","transactionId":"b2","status":"booked","bookingDate":"2018-10-01","amount":"5.00"}]`,
+	}
+	var fromDates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromDates = append(fromDates, r.URL.Query().Get("fromDate"))
+		w.Write([]byte(`{"response":{"transactions":` + responses[len(fromDates)-1] + `}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	store := &MemoryCursorStore{}
+	s := &Syncer{Client: c, Store: store, OverlapDays: 3}
+
+	events := collect(t, s, "account")
+	if len(events) != 3 {
+		t.Fatalf("first sync was incorrect, got: %v, want: 3 Added events.", events)
+	}
+	for _, event := range events {
+		if event.Type != Added {
+			t.Errorf("event type was incorrect, got: %s, want: %s.", event.Type, Added)
+		}
+	}
+
+	events = collect(t, s, "account")
+	if fromDates[1] != "2018-09-29" {
+		t.Errorf("fromDate was incorrect, got: %s, want: %s.", fromDates[1], "2018-09-29")
+	}
+	got := make(map[string]EventType)
+	for _, event := range events {
+		got[event.TransactionID] = event.Type
+	}
+	want := map[string]EventType{"p1": PendingBooked, "b1": Updated, "b2": Added, "p2": Removed}
+	if len(got) != len(want) {
+		t.Errorf("second sync was incorrect, got: %v, want: %v.", got, want)
+	}
+	for id, eventType := range want {
+		if got[id] != eventType {
+			t.Errorf("event for %s was incorrect, got: %s, want: %s.", id, got[id], eventType)
+		}
+	}
+
+	cursor, err := store.Load("account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.LastBookingDate.String() != "2018-10-05" {
+		t.Errorf("LastBookingDate was incorrect, got: %s, want: %s.", cursor.LastBookingDate, "2018-10-05")
+	}
+	if _, ok := cursor.Seen["b2"]; ok {
+		t.Errorf("cursor was incorrect, got: b2 outside of the overlap window, want: pruned.")
+	}
+}
+
+func TestFileCursorStore(t *testing.T) {
+	store := FileCursorStore{Dir: t.TempDir()}
+	if _, err := store.Load("SE/1"); err != ErrCursorNotFound {
+		t.Errorf("Load was incorrect, got: %v, want: %s.", err, ErrCursorNotFound)
+	}
+
+	cursor := Cursor{AccountID: "SE/1", LastBookingDate: nordeago.MustParseDate("2018-10-05"), Seen: map[string]SeenTransaction{"1": {Status: "booked", Hash: "abc"}}}
+	if err := store.Save("SE/1", cursor); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.Load("SE/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.LastBookingDate.String() != "2018-10-05" || loaded.Seen["1"].Hash != "abc" {
+		t.Errorf("Load was incorrect, got: %+v, want: %+v.", loaded, cursor)
+	}
+}
+
+func TestSyncUsesFingerprintsForMissingIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"transactions":[
+			{"_type":"DebitTransaction","status":"booked","bookingDate":"2018-10-01","amount":"10.00","counterpartName":"SL"},
+			{"_type":"DebitTransaction","status":"booked","bookingDate":"2018-10-01","amount":"10.00","counterpartName":"SL"}]}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	s := &Syncer{Client: c, Store: &MemoryCursorStore{}}
+	if events := collect(t, s, "account"); len(events) != 2 {
+		t.Errorf("first sync was incorrect, got: %d events, want: 2.", len(events))
+	}
+	if events := collect(t, s, "account"); len(events) != 0 {
+		t.Errorf("second sync was incorrect, got: %v, want: no events.", events)
+	}
+}
+
+func TestSyncLinksPendingToBookingWithNewID(t *testing.T) {
+	responses := []string{
+		`[{"_type":"DebitTransaction","transactionId":"p1","status":"pending","transactionDate":"2018-10-01","amount":"59.90","currency":"SEK","narrative":"ICA"}]`,
+		`[{"_type":"DebitTransaction","transactionId":"b1","status":"booked","bookingDate":"2018-10-02","amount":"59.90","currency":"SEK","narrative":"ICA MAXI"}]`,
+	}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"response":{"transactions":` + responses[calls-1] + `}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	s := &Syncer{Client: c, Store: &MemoryCursorStore{}}
+	collect(t, s, "account")
+
+	events := collect(t, s, "account")
+	if len(events) != 1 || events[0].Type != PendingBooked || events[0].TransactionID != "b1" || events[0].ReplacesID != "p1" {
+		t.Errorf("events were incorrect, got: %+v, want: a single PendingBooked event for b1 replacing p1.", events)
+	}
+}
+
+func TestSyncCancelledKeepsCursor(t *testing.T) {
+	responses := []string{
+		`[{"_type":"CreditTransaction","transactionId":"b1","status":"booked","bookingDate":"2018-10-01","amount":"100.00"}]`,
+		`[{"_type":"DebitTransaction","transactionId":"b3","status":"booked","bookingDate":"2018-10-03","amount":"30.00"},
+		  {"_type":"DebitTransaction","transactionId":"b2","status":"booked","bookingDate":"2018-10-02","amount":"20.00"},
+		  {"_type":"CreditTransaction","transactionId":"b1","status":"booked","bookingDate":"2018-10-01","amount":"100.00"}]`,
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[min(requests, len(responses)-1)]
+		requests++
+		w.Write([]byte(`{"response":{"transactions":` + response + `}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	s := &Syncer{Client: c, Store: &MemoryCursorStore{}, OverlapDays: 3}
+	if events := collect(t, s, "account"); len(events) != 1 {
+		t.Fatalf("first sync was incorrect, got: %v, want: 1 Added event.", events)
+	}
+
+	// The consumer stops after the first event, the sync is cancelled before the second event is sent
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan Event)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Sync(ctx, events, "account")
+	}()
+	first := <-events
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatalf("cancelled sync was incorrect, got: nil, want: context canceled.")
+	}
+
+	got := make(map[string]EventType)
+	for _, event := range collect(t, s, "account") {
+		got[event.TransactionID] = event.Type
+	}
+	if first.TransactionID != "b2" || got["b2"] != Added || got["b3"] != Added || len(got) != 2 {
+		t.Errorf("sync after cancel was incorrect, got: %s then %v, want: b2 then Added events for b2 and b3.", first.TransactionID, got)
+	}
+}
@@ -0,0 +1,300 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+// Default values used by a Syncer when the fields are not set
+const (
+	DefaultOverlapDays = 7
+	DefaultInitialDays = 90
+)
+
+// Syncer fetches new transactions for accounts and emits the changes since the last sync as events. The cursor of an
+// account is saved after all of its events have been sent.
+//
+//	syncer := &sync.Syncer{Client: &client, Store: sync.FileCursorStore{Dir: "cursors"}}
+//	events := make(chan sync.Event)
+//	go func() {
+//		for event := range events {
+//			fmt.Println(event.Type, event.TransactionID)
+//		}
+//	}()
+//	err := syncer.Watch(ctx, 5*time.Minute, events, accountIDs...)
+type Syncer struct {
+	Client *nordeago.Client
+	Store  CursorStore
+	// OverlapDays is the number of days before the last booking date that are fetched again to pick up late bookings,
+	// defaults to DefaultOverlapDays
+	OverlapDays int
+	// InitialDays is the number of days fetched the first time an account is synced, defaults to DefaultInitialDays
+	InitialDays int
+	// Location is used to determine the current banking day, defaults to nordeago.Stockholm
+	Location *time.Location
+	// UseFingerprints identifies transactions by ais.Fingerprints instead of the transaction id. Use it for accounts
+	// where the transaction ids are not stable between requests, transactions without an id always use the
+	// fingerprint.
+	UseFingerprints bool
+	// LinkOptions is used to link pending transactions to bookings with another transaction id
+	LinkOptions ais.LinkOptions
+	// OnError is called by Watch when a sync fails, the next sync is still made
+	OnError func(err error)
+}
+
+// Sync syncs the accounts one after another and sends the events to the channel. Errors for individual accounts are
+// joined and the remaining accounts are still synced.
+func (s *Syncer) Sync(ctx context.Context, events chan<- Event, accountIDs ...string) error {
+	var errs []error
+	for _, accountID := range accountIDs {
+		if err := s.syncAccount(ctx, events, accountID); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			errs = append(errs, fmt.Errorf("account %s: %w", accountID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Watch syncs the accounts immediately and then every interval until the context is done
+func (s *Syncer) Watch(ctx context.Context, interval time.Duration, events chan<- Event, accountIDs ...string) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx, events, accountIDs...); err != nil && ctx.Err() == nil && s.OnError != nil {
+			s.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Syncer) syncAccount(ctx context.Context, events chan<- Event, accountID string) error {
+	cursor, err := s.Store.Load(accountID)
+	if errors.Is(err, ErrCursorNotFound) {
+		cursor = Cursor{AccountID: accountID}
+	} else if err != nil {
+		return err
+	}
+	// The cursor is changed while the transactions are compared, work on a copy so that a store returning shared
+	// state is only updated by Save after all events have been sent
+	cursor = cursor.clone()
+	if cursor.Seen == nil {
+		cursor.Seen = make(map[string]SeenTransaction)
+	}
+
+	from := nordeago.DateOf(time.Now(), s.location()).AddDays(-s.initialDays())
+	if !cursor.LastBookingDate.IsZero() {
+		from = cursor.LastBookingDate.AddDays(-s.overlapDays())
+	}
+
+	var transactions []ais.Transaction
+	for transaction, err := range ais.Transactions(ctx, s.Client, accountID, ais.TransactionFilter{FromDate: from.In(s.location())}) {
+		if err != nil {
+			return err
+		}
+		transactions = append(transactions, transaction)
+	}
+	// The API returns the latest transactions first, events are sent oldest first
+	slices.Reverse(transactions)
+
+	var changes []Event
+	fingerprints := ais.Fingerprints(transactions)
+	returned := make(map[string]bool)
+	for i, transaction := range transactions {
+		data := transaction.Data()
+		hash, err := transactionHash(transaction)
+		if err != nil {
+			return err
+		}
+		id := data.TransactionID
+		if len(id) == 0 || s.UseFingerprints {
+			id = fingerprints[i]
+		}
+		if returned[id] {
+			continue
+		}
+		returned[id] = true
+
+		event := Event{AccountID: accountID, TransactionID: id, Transaction: transaction}
+		previous, ok := cursor.Seen[id]
+		switch {
+		case !ok:
+			event.Type = Added
+		case previous.Status.IsPending() && !data.Status.IsPending():
+			event.Type = PendingBooked
+			event.ReplacesID = id
+		case previous.Hash != hash:
+			event.Type = Updated
+		}
+		if event.Type != 0 {
+			changes = append(changes, event)
+		}
+
+		seen := SeenTransaction{Status: data.Status, BookingDate: data.BookingDate, Hash: hash}
+		if data.Status.IsPending() {
+			// Pending transactions are kept so that they can be linked to a booking with another transaction id
+			if seen.Transaction, err = json.Marshal(transaction); err != nil {
+				return err
+			}
+		}
+		cursor.Seen[id] = seen
+		if !data.Status.IsPending() && data.BookingDate.After(cursor.LastBookingDate) {
+			cursor.LastBookingDate = data.BookingDate
+		}
+	}
+
+	var removed []string
+	for id, seen := range cursor.Seen {
+		if !returned[id] && (seen.Status.IsPending() || !seen.BookingDate.Before(from)) {
+			removed = append(removed, id)
+		}
+	}
+	slices.Sort(removed)
+
+	changes, removed = s.linkPending(cursor, changes, removed)
+	for _, id := range removed {
+		delete(cursor.Seen, id)
+		changes = append(changes, Event{Type: Removed, AccountID: accountID, TransactionID: id})
+	}
+
+	// Booked transactions before the next window will never be returned again
+	next := cursor.LastBookingDate.AddDays(-s.overlapDays())
+	for id, seen := range cursor.Seen {
+		if !cursor.LastBookingDate.IsZero() && !seen.Status.IsPending() && !seen.BookingDate.IsZero() && seen.BookingDate.Before(next) {
+			delete(cursor.Seen, id)
+		}
+	}
+
+	for _, event := range changes {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return s.Store.Save(accountID, cursor)
+}
+
+// linkPending turns an Added booked transaction and a Removed pending transaction into a single PendingBooked event
+// when the booking replaced the pending transaction under another transaction id. The remaining removed ids are
+// returned.
+func (s *Syncer) linkPending(cursor Cursor, changes []Event, removed []string) ([]Event, []string) {
+	var pending []ais.Transaction
+	pendingIDs := make(map[ais.Transaction]string)
+	for _, id := range removed {
+		seen := cursor.Seen[id]
+		if !seen.Status.IsPending() || len(seen.Transaction) == 0 {
+			continue
+		}
+		transaction, err := ais.UnmarshalTransaction(seen.Transaction)
+		if err != nil {
+			continue
+		}
+		pending = append(pending, transaction)
+		pendingIDs[transaction] = id
+	}
+
+	var booked []ais.Transaction
+	for _, event := range changes {
+		if event.Type == Added && !event.Transaction.Data().Status.IsPending() {
+			booked = append(booked, event.Transaction)
+		}
+	}
+	if len(pending) == 0 || len(booked) == 0 {
+		return changes, removed
+	}
+
+	links, _ := ais.LinkPending(pending, booked, s.LinkOptions)
+	replaces := make(map[ais.Transaction]string, len(links))
+	linked := make(map[string]bool, len(links))
+	for _, link := range links {
+		replaces[link.Booked] = pendingIDs[link.Pending]
+		linked[pendingIDs[link.Pending]] = true
+	}
+
+	for i, event := range changes {
+		if id, ok := replaces[event.Transaction]; ok && event.Type == Added {
+			changes[i].Type = PendingBooked
+			changes[i].ReplacesID = id
+			delete(cursor.Seen, id)
+		}
+	}
+
+	var remaining []string
+	for _, id := range removed {
+		if !linked[id] {
+			remaining = append(remaining, id)
+		}
+	}
+	return changes, remaining
+}
+
+func (s *Syncer) overlapDays() int {
+	if s.OverlapDays > 0 {
+		return s.OverlapDays
+	}
+	return DefaultOverlapDays
+}
+
+func (s *Syncer) initialDays() int {
+	if s.InitialDays > 0 {
+		return s.InitialDays
+	}
+	return DefaultInitialDays
+}
+
+func (s *Syncer) location() *time.Location {
+	if s.Location != nil {
+		return s.Location
+	}
+	return nordeago.Stockholm
+}
+
+// transactionHash returns a hash of the transaction content used to detect updates
+func transactionHash(transaction ais.Transaction) (string, error) {
+	data, err := json.Marshal(transaction)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
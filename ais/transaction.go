@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// ErrTransactionNotFound is returned when a transaction could not be found for the account
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// TransactionDetail is returned from GetAccountTransaction and contains the CreditTransaction or DebitTransaction
+// along with its links
+type TransactionDetail struct {
+	Transaction
+	Links []nordeago.Link `json:"_links,omitempty"`
+}
+
+// TransactionSearch limits the search that is made when a transaction can not be fetched by id. Zero dates search
+// the last 90 days and a zero MaxTransactions looks at no more than 1000 transactions.
+type TransactionSearch struct {
+	FromDate        time.Time
+	ToDate          time.Time
+	MaxTransactions int
+}
+
+// GetAccountTransaction gets a single transaction by its transaction id. When the API does not support fetching a
+// single transaction, which it reports with 405 Method Not Allowed or 501 Not Implemented, the transactions of the
+// last 90 days are searched. Use FindAccountTransaction to search a different window. A 404 Not Found is returned as
+// an error since it means that the account or transaction does not exist.
+func GetAccountTransaction(ctx context.Context, c *nordeago.Client, accountID string, transactionID string) (*TransactionDetail, error) {
+	endpoint, err := nordeago.NewEndpoint("/accounts/{{accountId}}/transactions/{{transactionId}}").With("accountId", accountID).With("transactionId", transactionID).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	response, meta, err := nordeago.Do[TransactionDetail](ctx, c, http.MethodGet, endpoint, nil)
+
+	switch meta.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return FindAccountTransaction(ctx, c, accountID, transactionID, TransactionSearch{})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// FindAccountTransaction searches the transactions of the account for the transaction id within the bounds of the
+// search. ErrTransactionNotFound is returned if the transaction is not found.
+func FindAccountTransaction(ctx context.Context, c *nordeago.Client, accountID string, transactionID string, search TransactionSearch) (*TransactionDetail, error) {
+	filter := TransactionFilter{FromDate: search.FromDate, ToDate: search.ToDate}
+	if filter.ToDate.IsZero() {
+		filter.ToDate = time.Now()
+	}
+	if filter.FromDate.IsZero() {
+		filter.FromDate = filter.ToDate.AddDate(0, 0, -90)
+	}
+
+	it := NewTransactionsIterator(ctx, c, accountID, filter)
+	it.Limit = search.MaxTransactions
+	if it.Limit <= 0 {
+		it.Limit = 1000
+	}
+
+	for it.Next() {
+		if transaction := it.Transaction(); transaction.Data().TransactionID == transactionID {
+			return &TransactionDetail{Transaction: transaction}, nil
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrTransactionNotFound
+}
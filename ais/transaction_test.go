@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetAccountTransaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"_type":"DebitTransaction","transactionId":"abc","amount":"-10.00","_links":[{"rel":"self","href":"/v2/accounts/1/transactions/abc"}]}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	transaction, err := GetAccountTransaction(context.Background(), c, "1", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transaction.Data().TransactionID != "abc" || transaction.SignedAmount().Amount.String() != "-10.00" || len(transaction.Links) != 1 {
+		t.Errorf("GetAccountTransaction was incorrect, got: %+v.", transaction)
+	}
+}
+
+func TestGetAccountTransactionFallsBackToSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/accounts/2/transactions/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/v2/accounts/1/transactions" && r.URL.Path != "/v2/accounts/2/transactions" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.URL.Path == "/v2/accounts/2/transactions" {
+			t.Errorf("search was incorrect, got: a search after 404 Not Found.")
+		}
+		if r.URL.Query().Get("fromDate") == "" {
+			t.Errorf("search was incorrect, missing fromDate in %s.", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("continuationKey") {
+		case "":
+			w.Write([]byte(`{"response":{"continuationKey":"page2","transactions":[{"_type":"DebitTransaction","transactionId":"1"}]}}`))
+		default:
+			w.Write([]byte(`{"response":{"transactions":[{"_type":"CreditTransaction","transactionId":"abc","amount":"5.00"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	transaction, err := GetAccountTransaction(context.Background(), c, "1", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transaction.SignedAmount().Amount.String() != "5.00" {
+		t.Errorf("GetAccountTransaction was incorrect, got: %+v.", transaction)
+	}
+
+	if _, err := GetAccountTransaction(context.Background(), c, "2", "abc"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("GetAccountTransaction was incorrect, got: %v, want: 404 Not Found without a search.", err)
+	}
+
+	if _, err := FindAccountTransaction(context.Background(), c, "1", "missing", TransactionSearch{MaxTransactions: 1}); err != ErrTransactionNotFound {
+		t.Errorf("FindAccountTransaction was incorrect, got: %v, want: %v.", err, ErrTransactionNotFound)
+	}
+}
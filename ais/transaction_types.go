@@ -0,0 +1,193 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// Transaction types returned in the _type field
+const (
+	TransactionTypeCredit = "CreditTransaction"
+	TransactionTypeDebit  = "DebitTransaction"
+)
+
+// Data returns the fields of the transaction
+func (t *CreditTransaction) Data() *TransactionData {
+	return &t.TransactionData
+}
+
+// TransactionType returns CreditTransaction
+func (t *CreditTransaction) TransactionType() string {
+	return TransactionTypeCredit
+}
+
+// SignedAmount returns the amount as a positive value
+func (t *CreditTransaction) SignedAmount() nordeago.Money {
+	return nordeago.NewMoney(t.Amount.Abs(), t.Currency)
+}
+
+// MarshalJSON adds the _type field to the transaction
+func (t CreditTransaction) MarshalJSON() ([]byte, error) {
+	return marshalTransaction(TransactionTypeCredit, t.TransactionData, nil)
+}
+
+// Data returns the fields of the transaction
+func (t *DebitTransaction) Data() *TransactionData {
+	return &t.TransactionData
+}
+
+// TransactionType returns DebitTransaction
+func (t *DebitTransaction) TransactionType() string {
+	return TransactionTypeDebit
+}
+
+// SignedAmount returns the amount as a negative value
+func (t *DebitTransaction) SignedAmount() nordeago.Money {
+	return nordeago.NewMoney(t.Amount.Abs().Neg(), t.Currency)
+}
+
+// MarshalJSON adds the _type field to the transaction
+func (t DebitTransaction) MarshalJSON() ([]byte, error) {
+	return marshalTransaction(TransactionTypeDebit, t.TransactionData, nil)
+}
+
+// Data returns the fields of the transaction
+func (t *UnknownTransaction) Data() *TransactionData {
+	return &t.TransactionData
+}
+
+// TransactionType returns the _type returned by the API
+func (t *UnknownTransaction) TransactionType() string {
+	return t.Type
+}
+
+// SignedAmount returns the amount as returned by the API since the direction of the transaction is not known
+func (t *UnknownTransaction) SignedAmount() nordeago.Money {
+	return nordeago.NewMoney(t.Amount, t.Currency)
+}
+
+// MarshalJSON encodes the transaction with its _type
+func (t UnknownTransaction) MarshalJSON() ([]byte, error) {
+	return marshalTransaction(t.Type, t.TransactionData, nil)
+}
+
+// TransactionList is a list of transactions that is decoded into CreditTransaction and DebitTransaction values based on
+// the _type of each transaction. Transactions of other types are decoded into UnknownTransaction values so that a new
+// type in the API does not prevent the rest of the list from being decoded.
+type TransactionList []Transaction
+
+// UnmarshalJSON decodes each transaction into its type
+func (t *TransactionList) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	transactions := make(TransactionList, 0, len(raw))
+	for _, r := range raw {
+		transaction, err := UnmarshalTransaction(r)
+		if err != nil {
+			return err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	*t = transactions
+	return nil
+}
+
+// UnmarshalTransaction decodes a single transaction into a CreditTransaction or DebitTransaction depending on _type,
+// transactions with a missing or unknown _type are decoded into an UnknownTransaction
+func UnmarshalTransaction(data []byte) (Transaction, error) {
+	var header struct {
+		Type string `json:"_type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var transaction Transaction
+	switch header.Type {
+	case TransactionTypeCredit:
+		transaction = &CreditTransaction{}
+	case TransactionTypeDebit:
+		transaction = &DebitTransaction{}
+	default:
+		transaction = &UnknownTransaction{Type: header.Type, Raw: append(json.RawMessage(nil), data...)}
+	}
+
+	if err := json.Unmarshal(data, transaction.Data()); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// UnmarshalJSON decodes the transaction into its type along with the links
+func (t *TransactionDetail) UnmarshalJSON(data []byte) error {
+	transaction, err := UnmarshalTransaction(data)
+	if err != nil {
+		return err
+	}
+
+	var links struct {
+		Links []nordeago.Link `json:"_links,omitempty"`
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+
+	t.Transaction = transaction
+	t.Links = links.Links
+	return nil
+}
+
+// MarshalJSON encodes the transaction with its _type and links in the same format as the API returns it
+func (t TransactionDetail) MarshalJSON() ([]byte, error) {
+	if t.Transaction == nil {
+		return nil, errors.New("transaction detail has no transaction")
+	}
+	return marshalTransaction(t.TransactionType(), *t.Data(), t.Links)
+}
+
+func marshalTransaction(transactionType string, data TransactionData, links []nordeago.Link) ([]byte, error) {
+	return json.Marshal(struct {
+		Type string `json:"_type"`
+		TransactionData
+		Links []nordeago.Link `json:"_links,omitempty"`
+	}{transactionType, data, links})
+}
+
+// BalanceAfter returns the balance of the account after the transaction in the currency of the transaction
+func (t *TransactionData) BalanceAfter() nordeago.Money {
+	return nordeago.NewMoney(t.BalanceAfterTransaction, t.Currency)
+}
+
+// OriginalAmount returns the amount in the original currency for transactions made in a foreign currency
+func (t *TransactionData) OriginalAmount() nordeago.Money {
+	return nordeago.NewMoney(t.OriginalCurrencyAmount, t.OriginalCurrency)
+}
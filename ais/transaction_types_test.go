@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ais
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func TestTransactionListUnmarshalJSON(t *testing.T) {
+	var transactions TransactionList
+	err := json.Unmarshal([]byte(`[{"_type":"CreditTransaction","amount":"100.00","transactionId":"1"},{"_type":"DebitTransaction","amount":"25.50","transactionId":"2"},{"_type":"DebitTransaction","amount":"-3.00","transactionId":"3"}]`), &transactions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := transactions[0].(*CreditTransaction); !ok {
+		t.Errorf("transaction type was incorrect, got: %T, want: *ais.CreditTransaction.", transactions[0])
+	}
+	if _, ok := transactions[1].(*DebitTransaction); !ok {
+		t.Errorf("transaction type was incorrect, got: %T, want: *ais.DebitTransaction.", transactions[1])
+	}
+
+	valid := []string{"100.00", "-25.50", "-3.00"}
+	for i, transaction := range transactions {
+		if transaction.SignedAmount().Amount.String() != valid[i] {
+			t.Errorf("SignedAmount was incorrect, got: %s, want: %s.", transaction.SignedAmount(), valid[i])
+		}
+	}
+
+}
+
+func TestTransactionListUnknownType(t *testing.T) {
+	var transactions TransactionList
+	content := `[{"_type":"ReservationTransaction","transactionId":"1","amount":"-12.00","currency":"SEK","holdId":"h1"},{"transactionId":"2","amount":"5.00","currency":"SEK"},{"_type":"CreditTransaction","transactionId":"3","amount":"100.00","currency":"SEK"}]`
+	if err := json.Unmarshal([]byte(content), &transactions); err != nil {
+		t.Fatalf("UnmarshalJSON was incorrect, got: %v, want: no error for unknown types.", err)
+	}
+	if len(transactions) != 3 {
+		t.Fatalf("UnmarshalJSON was incorrect, got: %d transactions, want: 3.", len(transactions))
+	}
+
+	unknown, ok := transactions[0].(*UnknownTransaction)
+	if !ok {
+		t.Fatalf("transaction type was incorrect, got: %T, want: *ais.UnknownTransaction.", transactions[0])
+	}
+	if unknown.TransactionType() != "ReservationTransaction" || unknown.TransactionID != "1" || unknown.SignedAmount().String() != "-12.00 SEK" {
+		t.Errorf("UnknownTransaction was incorrect, got: %s %s %s.", unknown.TransactionType(), unknown.TransactionID, unknown.SignedAmount())
+	}
+	if !strings.Contains(string(unknown.Raw), `"holdId":"h1"`) {
+		t.Errorf("Raw was incorrect, got: %s, want: the transaction as returned by the API.", unknown.Raw)
+	}
+	if missing, ok := transactions[1].(*UnknownTransaction); !ok || len(missing.TransactionType()) > 0 {
+		t.Errorf("transaction without a type was incorrect, got: %T.", transactions[1])
+	}
+	if _, ok := transactions[2].(*CreditTransaction); !ok {
+		t.Errorf("transaction type was incorrect, got: %T, want: *ais.CreditTransaction.", transactions[2])
+	}
+}
+
+func TestTransactionMarshalJSON(t *testing.T) {
+	var transaction Transaction = &DebitTransaction{TransactionData{Amount: nordeago.MustParseDecimal("10.00"), TransactionID: "1"}}
+	content, err := json.Marshal(transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"_type":"DebitTransaction"`) {
+		t.Errorf("MarshalJSON was incorrect, got: %s, want _type DebitTransaction.", content)
+	}
+
+	decoded, err := UnmarshalTransaction(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TransactionType() != TransactionTypeDebit || decoded.Data().Amount.String() != "10.00" {
+		t.Errorf("round trip was incorrect, got: %+v.", decoded)
+	}
+}
+
+func TestTransactionDetailMarshalJSON(t *testing.T) {
+	content := `{"_type":"CreditTransaction","transactionId":"abc","amount":"10.00","currency":"SEK","_links":[{"rel":"self","href":"/v2/accounts/1/transactions/abc"}]}`
+	var detail TransactionDetail
+	if err := json.Unmarshal([]byte(content), &detail); err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"_type":"CreditTransaction"`) || !strings.Contains(string(encoded), `"transactionId":"abc"`) || strings.Contains(string(encoded), `"Transaction"`) {
+		t.Errorf("MarshalJSON was incorrect, got: %s, want: the transaction fields next to _links.", encoded)
+	}
+
+	var decoded TransactionDetail
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TransactionType() != TransactionTypeCredit || decoded.Data().TransactionID != "abc" || decoded.SignedAmount().String() != "10.00 SEK" || len(decoded.Links) != 1 || decoded.Links[0].Href != detail.Links[0].Href {
+		t.Errorf("round trip was incorrect, got: %+v, want: %+v.", decoded, detail)
+	}
+}
@@ -0,0 +1,157 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrOpaqueToken is returned by ParseTokenClaims when the access token is not a JWT
+var ErrOpaqueToken = errors.New("access token is not a JWT")
+
+// TokenClaims contains the information about an access token that is useful when making authorization decisions
+type TokenClaims struct {
+	Expiry   time.Time
+	IssuedAt time.Time
+	Scopes   []string
+	Accounts []string
+	PsuID    string
+	FromJWT  bool                   // False if the claims were taken from stored token metadata
+	Raw      map[string]interface{} // All claims decoded from the JWT payload
+}
+
+// ParseTokenClaims decodes the claims of a JWT access token without verifying its signature. The claims can not be
+// trusted and should only be used to avoid unnecessary requests, the API will still reject invalid tokens.
+// ErrOpaqueToken is returned if the token is not a JWT.
+func ParseTokenClaims(accessToken string) (TokenClaims, error) {
+	claims := TokenClaims{}
+
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return claims, ErrOpaqueToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, ErrOpaqueToken
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(payload)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims.Raw); err != nil {
+		return claims, ErrOpaqueToken
+	}
+
+	claims.FromJWT = true
+	claims.Expiry = claimTime(claims.Raw["exp"])
+	claims.IssuedAt = claimTime(claims.Raw["iat"])
+	claims.Scopes = claimStrings(claims.Raw, "scope", "scopes", "scp")
+	claims.Accounts = claimStrings(claims.Raw, "accounts", "account_list", "acc")
+	if psuIDs := claimStrings(claims.Raw, "psu_id", "psuId", "sub"); len(psuIDs) > 0 {
+		claims.PsuID = psuIDs[0]
+	}
+
+	return claims, nil
+}
+
+// Claims returns the claims of the access token if it is a JWT, otherwise the claims are built from the metadata
+// stored with the token
+func (t Token) Claims() (TokenClaims, error) {
+	claims, err := ParseTokenClaims(t.AccessToken)
+	if err == nil {
+		if claims.Expiry.IsZero() {
+			claims.Expiry = t.Expiry
+		}
+		return claims, nil
+	}
+	if !errors.Is(err, ErrOpaqueToken) {
+		return claims, err
+	}
+
+	return TokenClaims{
+		Expiry:   t.Expiry,
+		Scopes:   t.Scopes,
+		Accounts: t.Accounts,
+		PsuID:    t.PsuID,
+	}, nil
+}
+
+// Expired returns true if the claims contain an expiry time that has passed
+func (c TokenClaims) Expired() bool {
+	return !c.Expiry.IsZero() && time.Now().After(c.Expiry)
+}
+
+// HasScope returns true if the token was granted the scope
+func (c TokenClaims) HasScope(scope string) bool {
+	return containsString(c.Scopes, scope)
+}
+
+// HasAccount returns true if the token gives access to the account
+func (c TokenClaims) HasAccount(accountID string) bool {
+	return containsString(c.Accounts, accountID)
+}
+
+func claimTime(value interface{}) time.Time {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}
+	}
+	seconds, err := number.Int64()
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// claimStrings returns the first claim found with one of the names, scopes are usually space separated strings while
+// other claims can be arrays
+func claimStrings(raw map[string]interface{}, names ...string) []string {
+	for _, name := range names {
+		switch value := raw[name].(type) {
+		case string:
+			return strings.Fields(value)
+		case []interface{}:
+			var values []string
+			for _, v := range value {
+				if s, ok := v.(string); ok {
+					values = append(values, s)
+				}
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestParseTokenClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1893456000,"scope":"ACCOUNTS_BASIC ACCOUNTS_TRANSACTIONS","accounts":["41770042136"],"psu_id":"193805010844"}`))
+	claims, err := ParseTokenClaims("eyJhbGciOiJIUzI1NiJ9." + payload + ".signature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.Expiry.Equal(time.Unix(1893456000, 0)) {
+		t.Errorf("Expiry was incorrect, got: %s, want: %s.", claims.Expiry, time.Unix(1893456000, 0))
+	}
+	if !claims.HasScope("ACCOUNTS_TRANSACTIONS") || !claims.HasAccount("41770042136") || claims.PsuID != "193805010844" {
+		t.Errorf("claims were incorrect, got: %+v.", claims)
+	}
+}
+
+func TestTokenClaimsOpaqueFallback(t *testing.T) {
+	if _, err := ParseTokenClaims("opaque-token"); err != ErrOpaqueToken {
+		t.Errorf("ParseTokenClaims was incorrect, got: %v, want: %v.", err, ErrOpaqueToken)
+	}
+
+	token := Token{AccessToken: "opaque-token", Scopes: []string{"ACCOUNTS_BALANCES"}, PsuID: "193805010844"}
+	claims, err := token.Claims()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.FromJWT || !claims.HasScope("ACCOUNTS_BALANCES") || claims.PsuID != "193805010844" {
+		t.Errorf("claims were incorrect, got: %+v.", claims)
+	}
+}
@@ -25,15 +25,19 @@ package nordeago
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"path"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 // Client holds all the needed information to communicate with the nordea API. Use InitClient to create a new Client.
+// A Client must not be copied after it has been used to make requests.
 type Client struct {
 	BaseURL      string
 	Protocol     string
@@ -43,25 +47,39 @@ type Client struct {
 	TppToken     string
 	RedirectURL  string
 	AuthCode     string
-	AccessToken  string
+	AccessToken  string // Use GetAccessToken and SetAccessToken while requests are made concurrently
+	RefreshToken string
+
+	// ReauthHandler is called when a request made with an access token is rejected because the token was revoked,
+	// has expired or the consent has expired. The original request is replayed once if the handler succeeds.
+	ReauthHandler ReauthHandler
+
+	tokenMu    sync.RWMutex
+	reauthMu   sync.Mutex
+	reauthCall *reauthCall // The reauthentication in progress, guarded by reauthMu
 }
 
 // InitClient creates a new client from a clientID and clientSecret. You can find this information by signing up for free
 // at https://developer.nordeaopenbanking.com
 func InitClient(clientID string, clientSecret string, redirectURL string) Client {
-	c := Client{}
-	c.Protocol = "https://"
-	c.BaseURL = "api.nordeaopenbanking.com"
-	c.Version = "v2"
-	c.ClientID = clientID
-	c.ClientSecret = clientSecret
-	c.RedirectURL = redirectURL
-	return c
+	return Client{
+		Protocol:     "https://",
+		BaseURL:      "api.nordeaopenbanking.com",
+		Version:      "v2",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	}
 }
 
 // request handles requests to the Nordea API
-func (c *Client) request(requestType string, endpoint string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(requestType, c.GetFullURL(endpoint), body)
+func (c *Client) request(ctx context.Context, requestType string, endpoint string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	fullURL, err := c.requestURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, requestType, fullURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +107,7 @@ func (c *Client) Post(endpoint string, requestObject interface{}, headers map[st
 
 	requestReader := bytes.NewReader(requestByte)
 
-	return c.request("POST", endpoint, requestReader, headers)
+	return c.request(context.Background(), "POST", endpoint, requestReader, headers)
 }
 
 // PostWithAccessToken handles post requests by converting request types to json and passing the data to Request and also setting the relevant headers to authenticate with an access token
@@ -100,13 +118,11 @@ func (c *Client) PostWithAccessToken(endpoint string, requestObject interface{},
 		return nil, err
 	}
 
-	headers = c.setAccessTokenHeaders(headers)
+	headers = initHeaders(headers)
 
 	headers["Accept"] = "application/json"
 
-	requestReader := bytes.NewReader(requestByte)
-
-	return c.request("POST", endpoint, requestReader, headers)
+	return c.requestWithAccessToken(context.Background(), "POST", endpoint, requestByte, headers)
 }
 
 // Get handles get requests by setting the type of request to GET along with a nil body and calling Request
@@ -115,25 +131,23 @@ func (c *Client) Get(endpoint string, headers map[string]string) (*http.Response
 
 	headers["Accept"] = "application/json"
 
-	return c.request("GET", endpoint, nil, headers)
+	return c.request(context.Background(), "GET", endpoint, nil, headers)
 }
 
 // GetWithAccessToken handles get requests by setting the type of request to GET along with a nil body and calling Request with the needed headers
 func (c *Client) GetWithAccessToken(endpoint string, headers map[string]string) (*http.Response, error) {
-	headers = c.setAccessTokenHeaders(headers)
-	return c.request("GET", endpoint, nil, headers)
+	return c.requestWithAccessToken(context.Background(), "GET", endpoint, nil, headers)
 }
 
 // Put handles put requests by setting the type of request to PUT along with a nil body and calling Request
 func (c *Client) Put(endpoint string, headers map[string]string) (*http.Response, error) {
 	headers = initHeaders(headers)
-	return c.request("PUT", endpoint, nil, headers)
+	return c.request(context.Background(), "PUT", endpoint, nil, headers)
 }
 
 // PutWithAccessToken handles put requests by setting the type of request to PUT along with a nil body and calling Request with the needed headers
 func (c *Client) PutWithAccessToken(endpoint string, headers map[string]string) (*http.Response, error) {
-	headers = c.setAccessTokenHeaders(headers)
-	return c.request("PUT", endpoint, nil, headers)
+	return c.requestWithAccessToken(context.Background(), "PUT", endpoint, nil, headers)
 }
 
 // Delete handles delete requests by setting the type of request to DELETE along with a nil body and calling Request
@@ -142,27 +156,60 @@ func (c *Client) Delete(endpoint string, headers map[string]string) (*http.Respo
 
 	headers["Accept"] = "application/json"
 
-	return c.request("DELETE", endpoint, nil, headers)
+	return c.request(context.Background(), "DELETE", endpoint, nil, headers)
 }
 
 // DeleteWithAccessToken handles delete requests by setting the type of request to DELETE along with a nil body and calling Request with the needed headers
 func (c *Client) DeleteWithAccessToken(endpoint string, headers map[string]string) (*http.Response, error) {
-	headers = c.setAccessTokenHeaders(headers)
+	headers = initHeaders(headers)
 
 	headers["Accept"] = "application/json"
 
-	return c.request("DELETE", endpoint, nil, headers)
+	return c.requestWithAccessToken(context.Background(), "DELETE", endpoint, nil, headers)
 }
 
-// GetFullURL builds the endpoint url from the client configuration combining it with the supplied endpoint
+// GetFullURL builds the endpoint url from the client configuration combining it with the supplied endpoint. The
+// endpoint is expected to be escaped already, see Endpoint. Absolute urls, such as links returned by the API, are
+// returned unchanged, but requests are only made to them when they use the scheme and host of the base url.
 func (c *Client) GetFullURL(endpoint string) string {
-	return c.Protocol + path.Join(c.BaseURL, c.Version, endpoint)
+	if u, err := url.Parse(endpoint); err == nil && u.IsAbs() {
+		return endpoint
+	}
+
+	fullURL := c.Protocol + strings.Trim(c.BaseURL, "/")
+	if version := strings.Trim(c.Version, "/"); len(version) > 0 {
+		fullURL += "/" + version
+	}
+
+	return fullURL + "/" + strings.TrimLeft(endpoint, "/")
+}
+
+// requestURL returns the full url of the endpoint like GetFullURL. Absolute urls to another scheme or host than the
+// base url return ErrForeignLink since the request would carry the access token and client credentials.
+func (c *Client) requestURL(endpoint string) (string, error) {
+	fullURL := c.GetFullURL(endpoint)
+	if u, err := url.Parse(endpoint); err != nil || !u.IsAbs() {
+		return fullURL, nil
+	}
+
+	requested, err := url.Parse(fullURL)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(c.GetFullURL(""))
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(requested.Scheme, base.Scheme) || !strings.EqualFold(requested.Host, base.Host) {
+		return "", fmt.Errorf("%w: %s", ErrForeignLink, requested.Redacted())
+	}
+	return fullURL, nil
 }
 
 func (c *Client) setAccessTokenHeaders(headers map[string]string) map[string]string {
 	headers = initHeaders(headers)
 
-	headers["Authorization"] = BearerAuthHeader(c.AccessToken)
+	headers["Authorization"] = BearerAuthHeader(c.GetAccessToken())
 	headers["X-IBM-Client-Id"] = c.ClientID
 	headers["X-IBM-Client-Secret"] = c.ClientSecret
 
@@ -179,6 +226,8 @@ func initHeaders(headers map[string]string) map[string]string {
 }
 
 // HandleResponse takes a http response and unmarshals the json content if possible or otherwise returns a status code and/or error
+//
+// Deprecated: use Do or DoRequest which decode the response into a typed value.
 func (c *Client) HandleResponse(response *http.Response, result *Result) (int, error) {
 	decoder := json.NewDecoder(response.Body)
 
@@ -0,0 +1,291 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	// Banking locations are needed on systems without a time zone database
+	_ "time/tzdata"
+)
+
+// dateLayouts are the formats the API uses for calendar dates
+var dateLayouts = []string{"2006-01-02", "20060102"}
+
+// timestampLayouts are the formats the API uses for timestamps, layouts without a zone are parsed as UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Banking locations of the Nordic countries. Calendar dates returned by the API, such as booking dates, are banking
+// days in the country of the account.
+var (
+	Stockholm  = mustLoadLocation("Europe/Stockholm")
+	Helsinki   = mustLoadLocation("Europe/Helsinki")
+	Copenhagen = mustLoadLocation("Europe/Copenhagen")
+	Oslo       = mustLoadLocation("Europe/Oslo")
+)
+
+// BankingLocation returns the location used for banking days in a country, Finland is used for unknown countries as
+// it is the default country of the API
+func BankingLocation(country string) *time.Location {
+	switch strings.ToUpper(country) {
+	case "SE":
+		return Stockholm
+	case "DK":
+		return Copenhagen
+	case "NO":
+		return Oslo
+	default:
+		return Helsinki
+	}
+}
+
+// Date is a calendar date without a time zone such as a booking date. It is marshaled back in the format it was
+// parsed with. The zero value is an unset date which is omitted from json when the field is tagged with omitzero.
+type Date struct {
+	year  int
+	month time.Month
+	day   int
+	raw   string
+}
+
+// NewDate creates a date, the values are normalized the same way as time.Date
+func NewDate(year int, month time.Month, day int) Date {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return Date{year: t.Year(), month: t.Month(), day: t.Day()}
+}
+
+// DateOf returns the calendar date of t in the location, use BankingLocation to get the banking day of an instant
+func DateOf(t time.Time, location *time.Location) Date {
+	t = t.In(location)
+	return NewDate(t.Year(), t.Month(), t.Day())
+}
+
+// ParseDate parses a date in one of the formats returned by the API, timestamps are truncated to their date
+func ParseDate(value string) (Date, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			d := NewDate(t.Year(), t.Month(), t.Day())
+			d.raw = value
+			return d, nil
+		}
+	}
+	if t, err := ParseTimestamp(value); err == nil {
+		d := NewDate(t.Year(), t.Month(), t.Day())
+		d.raw = value
+		return d, nil
+	}
+	return Date{}, fmt.Errorf("invalid date %q", value)
+}
+
+// MustParseDate is like ParseDate but panics if the value is invalid, it is meant for constants
+func MustParseDate(value string) Date {
+	d, err := ParseDate(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Year returns the year of the date
+func (d Date) Year() int {
+	return d.year
+}
+
+// Month returns the month of the date
+func (d Date) Month() time.Month {
+	return d.month
+}
+
+// Day returns the day of the month
+func (d Date) Day() int {
+	return d.day
+}
+
+// IsZero returns true if the date is unset
+func (d Date) IsZero() bool {
+	return d.year == 0 && d.month == 0 && d.day == 0
+}
+
+// In returns the start of the day in the location, for example In(Stockholm) for a Swedish booking date
+func (d Date) In(location *time.Location) time.Time {
+	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, location)
+}
+
+// AddDays returns the date n days later
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.year, d.month, d.day+n)
+}
+
+// Compare returns -1 if d is before other, 1 if it is after and 0 if they are the same date
+func (d Date) Compare(other Date) int {
+	a, b := d.In(time.UTC), other.In(time.UTC)
+	return a.Compare(b)
+}
+
+// Before returns true if d is before other
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// After returns true if d is after other
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// Equal returns true if both dates are the same calendar date regardless of the format they were parsed from
+func (d Date) Equal(other Date) bool {
+	return d.Compare(other) == 0
+}
+
+// IsWeekend returns true for Saturdays and Sundays which are never banking days
+func (d Date) IsWeekend() bool {
+	weekday := d.In(time.UTC).Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// String returns the date in the format it was parsed from or as YYYY-MM-DD
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	if len(d.raw) > 0 {
+		return d.raw
+	}
+	return d.In(time.UTC).Format(dateLayouts[0])
+}
+
+// MarshalJSON encodes the date as a string, unset dates are encoded as null
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a date string, empty strings and null are decoded as an unset date
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value, ok, err := unmarshalTimeString(data)
+	if err != nil || !ok {
+		*d = Date{}
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Timestamp is an instant returned by the API such as the creation time of a response. It is marshaled back exactly
+// as it was parsed until the time is changed, timestamps without a zone are treated as UTC.
+type Timestamp struct {
+	time.Time
+	layout string
+	raw    string
+	parsed time.Time
+}
+
+// ParseTimestamp parses a timestamp in one of the formats returned by the API
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{Time: t, layout: layout, raw: value, parsed: t}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// BankingDate returns the banking day of the timestamp in the country, see BankingLocation
+func (t Timestamp) BankingDate(country string) Date {
+	return DateOf(t.Time, BankingLocation(country))
+}
+
+// String returns the timestamp as it was parsed, or in the layout it was parsed with if the time has been changed
+// since. Timestamps that were not parsed are formatted as RFC 3339.
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	if len(t.raw) > 0 && t.Time.Equal(t.parsed) {
+		return t.raw
+	}
+	layout := t.layout
+	if len(layout) == 0 {
+		layout = time.RFC3339Nano
+	}
+	return t.Format(layout)
+}
+
+// MarshalJSON encodes the timestamp as a string, unset timestamps are encoded as null
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a timestamp string, empty strings and null are decoded as an unset timestamp
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	value, ok, err := unmarshalTimeString(data)
+	if err != nil || !ok {
+		*t = Timestamp{}
+		return err
+	}
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// unmarshalTimeString returns the string value and false if the value is null or empty
+func unmarshalTimeString(data []byte) (string, bool, error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return "", false, nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", false, err
+	}
+	value = strings.TrimSpace(value)
+	return value, len(value) > 0, nil
+}
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
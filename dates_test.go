@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateJSONRoundTrip(t *testing.T) {
+	for _, value := range []string{`"2018-10-01"`, `"20181001"`, `"2018-10-01T00:00:00Z"`} {
+		var d Date
+		if err := json.Unmarshal([]byte(value), &d); err != nil {
+			t.Fatal(err)
+		}
+		if !d.Equal(NewDate(2018, time.October, 1)) {
+			t.Errorf("Date was incorrect, got: %d-%d-%d, want: 2018-10-01.", d.Year(), d.Month(), d.Day())
+		}
+		content, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != value {
+			t.Errorf("Date round trip was incorrect, got: %s, want: %s.", content, value)
+		}
+	}
+
+	var d Date
+	if err := json.Unmarshal([]byte(`""`), &d); err != nil || !d.IsZero() {
+		t.Errorf("empty date was incorrect, got: %v and error %v, want an unset date.", d, err)
+	}
+}
+
+func TestTimestampJSONRoundTrip(t *testing.T) {
+	values := []string{
+		`"2018-10-01T12:30:00.123+02:00"`,
+		`"2018-10-01T12:30:00.120+02:00"`,
+		`"2018-10-01T10:30:00.000Z"`,
+		`"2018-10-01T10:30:00.000000+00:00"`,
+		`"2018-10-01T12:30:00+0200"`,
+		`"2018-10-01T10:30:00"`,
+	}
+	for _, value := range values {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(value), &ts); err != nil {
+			t.Fatal(err)
+		}
+		if ts.UTC().Hour() != 10 || ts.UTC().Minute() != 30 {
+			t.Errorf("Timestamp was incorrect, got: %s, want: 10:30 UTC.", ts.UTC())
+		}
+		content, err := json.Marshal(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != value {
+			t.Errorf("Timestamp round trip was incorrect, got: %s, want: %s.", content, value)
+		}
+	}
+}
+
+func TestTimestampChanged(t *testing.T) {
+	ts, err := ParseTimestamp("2018-10-01T12:30:00.000+02:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Time = ts.Add(time.Hour)
+	if ts.String() != "2018-10-01T13:30:00+02:00" {
+		t.Errorf("changed Timestamp was incorrect, got: %s, want: 2018-10-01T13:30:00+02:00.", ts)
+	}
+}
+
+func TestBankingDate(t *testing.T) {
+	// 23:30 UTC on the 1st is already the 2nd in both Stockholm and Helsinki
+	ts, err := ParseTimestamp("2018-10-01T23:30:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := ts.BankingDate("SE"); !d.Equal(NewDate(2018, time.October, 2)) {
+		t.Errorf("BankingDate was incorrect, got: %s, want: 2018-10-02.", d)
+	}
+
+	// Helsinki is one hour ahead of Stockholm
+	start := NewDate(2018, time.October, 2).In(Helsinki)
+	if DateOf(start, Stockholm).Equal(NewDate(2018, time.October, 2)) {
+		t.Errorf("DateOf was incorrect, midnight in Helsinki is still the previous day in Stockholm.")
+	}
+	if !NewDate(2018, time.October, 6).IsWeekend() || NewDate(2018, time.October, 5).IsWeekend() {
+		t.Errorf("IsWeekend was incorrect.")
+	}
+}
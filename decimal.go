@@ -0,0 +1,254 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal is an arbitrary precision decimal number used for amounts and rates. It keeps the number of decimals it
+// was parsed with so that amounts returned by the API are encoded exactly the same way. The zero value is an unset
+// amount which is treated as 0 in arithmetic and is omitted from json when the field is tagged with omitzero.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// NewDecimal returns unscaled * 10^-scale, for example NewDecimal(12345, 2) is 123.45
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{unscaled: new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))}
+	}
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parses a decimal number such as "-1234.50", the number of decimals is kept
+func ParseDecimal(value string) (Decimal, error) {
+	s := strings.TrimSpace(value)
+	if len(s) == 0 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	digits := s
+	if digits[0] == '+' || digits[0] == '-' {
+		digits = digits[1:]
+	}
+
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+	if len(integer)+len(fraction) == 0 || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	unscaled, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+	if s[0] == '-' {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{unscaled: unscaled, scale: int32(len(fraction))}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if the value is invalid, it is meant for constants
+func MustParseDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// IsSet returns false for the zero value which represents a missing amount
+func (d Decimal) IsSet() bool {
+	return d.unscaled != nil
+}
+
+// Scale returns the number of decimals
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the decimal
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// Cmp compares two decimals and returns -1, 0 or 1
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := align(d, other)
+	return a.Cmp(b)
+}
+
+// Add returns d + other using the largest scale of the two
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b := align(d, other)
+	return Decimal{unscaled: new(big.Int).Add(a, b), scale: maxScale(d, other)}
+}
+
+// Sub returns d - other using the largest scale of the two
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b := align(d, other)
+	return Decimal{unscaled: new(big.Int).Sub(a, b), scale: maxScale(d, other)}
+}
+
+// Mul returns d * other, the scale of the result is the sum of the scales
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale}
+}
+
+// Quo returns d / other rounded to the number of decimals, halves are rounded away from zero. It panics if other is
+// zero.
+func (d Decimal) Quo(other Decimal, scale int32) Decimal {
+	if scale < 0 {
+		return d.Quo(other, 0).Round(scale)
+	}
+
+	numerator := new(big.Int).Mul(d.int(), pow10(other.scale+scale))
+	denominator := new(big.Int).Mul(other.int(), pow10(d.scale))
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).CmpAbs(denominator) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(numerator.Sign()*denominator.Sign())))
+	}
+	return Decimal{unscaled: quotient, scale: scale}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns the absolute value of d
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Round returns d rounded to the number of decimals, halves are rounded away from zero. A negative scale rounds to a
+// power of ten, for example Round(-1) rounds 12.5 to 10.
+func (d Decimal) Round(scale int32) Decimal {
+	if scale >= d.scale {
+		return Decimal{unscaled: new(big.Int).Mul(d.int(), pow10(scale-d.scale)), scale: scale}
+	}
+
+	divisor := pow10(d.scale - scale)
+	quotient, remainder := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(d.Sign())))
+	}
+	if scale < 0 {
+		return Decimal{unscaled: quotient.Mul(quotient, pow10(-scale))}
+	}
+	return Decimal{unscaled: quotient, scale: scale}
+}
+
+// String returns the decimal with its number of decimals, for example "-1234.50"
+func (d Decimal) String() string {
+	unscaled := d.int()
+	digits := new(big.Int).Abs(unscaled).String()
+
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits
+	}
+
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// MarshalJSON encodes the decimal as a string the same way the API does
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a decimal from a string or a number, empty strings and null are decoded as an unset amount
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		if len(strings.TrimSpace(value)) == 0 {
+			*d = Decimal{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// align returns the unscaled values of both decimals at the largest scale of the two
+func align(a Decimal, b Decimal) (*big.Int, *big.Int) {
+	scale := maxScale(a, b)
+	return new(big.Int).Mul(a.int(), pow10(scale-a.scale)), new(big.Int).Mul(b.int(), pow10(scale-b.scale))
+}
+
+func maxScale(a Decimal, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Request describes a request made with DoRequest
+type Request struct {
+	Method          string
+	Endpoint        string
+	Body            interface{}       // Encoded as json if not nil
+	Headers         map[string]string // Additional headers such as X-Response-Scenarios
+	SkipAccessToken bool              // Set for Identity and Access API requests that are made before an access token exists
+}
+
+// ResponseMeta contains information about a response that is not part of the decoded response type
+type ResponseMeta struct {
+	StatusCode  int
+	Header      http.Header
+	GroupHeader GroupHeader
+}
+
+// ContinuationKey returns the first continuation key of the message pagination or an empty string if there are no
+// more results
+func (m ResponseMeta) ContinuationKey() string {
+	for _, pagination := range m.GroupHeader.MessagePagination {
+		if len(pagination.ContinuationKey) > 0 {
+			return pagination.ContinuationKey
+		}
+	}
+	return ""
+}
+
+// Do makes a request using the access token of the client and decodes the response into T. Responses wrapped in a
+// Result have their GroupHeader decoded into ResponseMeta, other responses are decoded directly into T.
+func Do[T any](ctx context.Context, c *Client, method string, endpoint string, body interface{}) (T, ResponseMeta, error) {
+	return DoRequest[T](ctx, c, Request{Method: method, Endpoint: endpoint, Body: body})
+}
+
+// DoRequest makes the request and decodes the response into T, see Do
+func DoRequest[T any](ctx context.Context, c *Client, r Request) (T, ResponseMeta, error) {
+	var responseType T
+	meta, err := c.do(ctx, r, &responseType)
+	return responseType, meta, err
+}
+
+// do makes the request and decodes the response into responseType which must be a pointer
+func (c *Client) do(ctx context.Context, r Request, responseType interface{}) (ResponseMeta, error) {
+	meta := ResponseMeta{}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = json.Marshal(r.Body)
+		if err != nil {
+			return meta, err
+		}
+	}
+
+	headers := initHeaders(nil)
+	for key, value := range r.Headers {
+		headers[key] = value
+	}
+	headers["Accept"] = "application/json"
+
+	var response *http.Response
+	var err error
+	if r.SkipAccessToken {
+		response, err = c.request(ctx, r.Method, r.Endpoint, bodyReader(body), headers)
+	} else {
+		response, err = c.requestWithAccessToken(ctx, r.Method, r.Endpoint, body, headers)
+	}
+
+	if err != nil {
+		return meta, err
+	}
+
+	defer response.Body.Close()
+
+	meta.StatusCode = response.StatusCode
+	meta.Header = response.Header
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return meta, err
+	}
+
+	// TODO check APIm-Debug-Trans-Id, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-Global-Transaction-ID headers
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return meta, responseError(response.StatusCode, content)
+	}
+
+	return meta, decodeResponse(content, responseType, &meta)
+}
+
+// decodeResponse decodes a Result into the response type or the whole content if the response is not wrapped
+func decodeResponse(content []byte, responseType interface{}, meta *ResponseMeta) error {
+	if len(content) == 0 {
+		return nil
+	}
+
+	var result struct {
+		GroupHeader GroupHeader     `json:"groupHeader"`
+		Response    json.RawMessage `json:"response"`
+	}
+
+	if json.Unmarshal(content, &result) == nil && result.Response != nil {
+		meta.GroupHeader = result.GroupHeader
+		return json.Unmarshal(result.Response, responseType)
+	}
+
+	return json.Unmarshal(content, responseType)
+}
+
+// responseError builds an error from the error response, redirects such as 304 Not Modified are not errors
+func responseError(statusCode int, content []byte) error {
+	var result struct {
+		Error ErrorResponse `json:"error"`
+	}
+	if json.Unmarshal(content, &result) == nil && len(result.Error.Failures) > 0 {
+		errorString := fmt.Sprintf("%d - %s: %s", result.Error.HTTPCode, result.Error.HTTPMessage, result.Error.MoreInformation)
+		for _, failure := range result.Error.Failures {
+			errorString += fmt.Sprintf("\n%s: %s", failure.Code, failure.Description)
+		}
+		return errors.New(errorString)
+	}
+
+	var errorResponse ErrorResponse
+	if json.Unmarshal(content, &errorResponse) == nil && len(errorResponse.HTTPMessage) > 0 {
+		return fmt.Errorf("%d - %s: %s", errorResponse.HTTPCode, errorResponse.HTTPMessage, errorResponse.MoreInformation)
+	}
+
+	if statusCode < 400 {
+		return nil
+	}
+
+	return fmt.Errorf("%d - %s", statusCode, http.StatusText(statusCode))
+}
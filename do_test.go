@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoDecodesResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"groupHeader":{"messageIdentification":"abc","httpCode":200,"messagePagination":[{"continuationKey":"next"}]},"response":{"name":"Markus"}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	response, meta, err := Do[struct {
+		Name string `json:"name"`
+	}](context.Background(), c, http.MethodGet, "/accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Name != "Markus" || meta.GroupHeader.MessageIdentification != "abc" || meta.StatusCode != http.StatusOK {
+		t.Errorf("Do was incorrect, got: %+v and %+v.", response, meta)
+	}
+}
+
+func TestDoDecodesUnwrappedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"token"}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	response, _, err := Do[Token](context.Background(), c, http.MethodPost, "/authorize/access_token", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.AccessToken != "token" {
+		t.Errorf("Do was incorrect, got: %s, want: token.", response.AccessToken)
+	}
+}
+
+func TestDoReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"httpCode":400,"httpMessage":"Bad Request","moreInformation":"Invalid request","failures":[{"code":"error.validation","description":"accountId is invalid"}]}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	_, meta, err := Do[Token](context.Background(), c, http.MethodGet, "/accounts/x", nil)
+	valid := "400 - Bad Request: Invalid request\nerror.validation: accountId is invalid"
+	if err == nil || err.Error() != valid || meta.StatusCode != http.StatusBadRequest {
+		t.Errorf("Do error was incorrect, got: %v, want: %s.", err, valid)
+	}
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var endpointVariable = regexp.MustCompile(`{{(\w+)}}`)
+
+// Endpoint builds a path relative to the API version from a template such as "/accounts/{{accountId}}". Path
+// parameters are escaped according to RFC 3986 so that ids containing reserved characters stay in their own segment.
+type Endpoint struct {
+	template string
+	params   map[string]string
+	query    url.Values
+}
+
+// NewEndpoint creates an Endpoint from a template where path parameters are written as {{name}}
+func NewEndpoint(template string) *Endpoint {
+	return &Endpoint{template: template, params: make(map[string]string)}
+}
+
+// With sets the value of a path parameter
+func (e *Endpoint) With(name string, value string) *Endpoint {
+	e.params[name] = value
+	return e
+}
+
+// WithQuery adds the values to the query string of the endpoint
+func (e *Endpoint) WithQuery(query url.Values) *Endpoint {
+	if e.query == nil {
+		e.query = make(url.Values)
+	}
+	for key, values := range query {
+		for _, value := range values {
+			e.query.Add(key, value)
+		}
+	}
+	return e
+}
+
+// Build returns the escaped endpoint. An error is returned if a path parameter is missing, empty or not part of the
+// template as that would result in a request to a different resource.
+func (e *Endpoint) Build() (string, error) {
+	var missing []string
+	used := make(map[string]bool)
+
+	endpoint := endpointVariable.ReplaceAllStringFunc(e.template, func(variable string) string {
+		name := endpointVariable.FindStringSubmatch(variable)[1]
+		value, ok := e.params[name]
+		if !ok || len(value) == 0 {
+			missing = append(missing, name)
+			return variable
+		}
+		used[name] = true
+		return escapePathSegment(value)
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing path parameter %s for endpoint %s", strings.Join(missing, ", "), e.template)
+	}
+	for name := range e.params {
+		if !used[name] {
+			return "", fmt.Errorf("unknown path parameter %s for endpoint %s", name, e.template)
+		}
+	}
+
+	if len(e.query) > 0 {
+		endpoint += "?" + e.query.Encode()
+	}
+
+	return endpoint, nil
+}
+
+// escapePathSegment escapes a single path segment, dot segments are encoded as they would otherwise be removed when
+// the path is normalized
+func escapePathSegment(value string) string {
+	if value == "." || value == ".." {
+		return strings.Replace(value, ".", "%2E", -1)
+	}
+	return url.PathEscape(value)
+}
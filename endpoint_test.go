@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestEndpointBuild(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid string
+	}{
+		{"41770042136", "/accounts/41770042136/transactions"},
+		{"FI65 9385/7450?#", "/accounts/FI65%209385%2F7450%3F%23/transactions"},
+		{"..", "/accounts/%2E%2E/transactions"},
+		{"åäö", "/accounts/%C3%A5%C3%A4%C3%B6/transactions"},
+		{"50%", "/accounts/50%25/transactions"},
+	}
+
+	for _, test := range tests {
+		endpoint, err := NewEndpoint("/accounts/{{accountId}}/transactions").With("accountId", test.id).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if endpoint != test.valid {
+			t.Errorf("Build was incorrect, got: %s, want: %s.", endpoint, test.valid)
+		}
+	}
+}
+
+func TestEndpointBuildQuery(t *testing.T) {
+	query := url.Values{}
+	query.Set("fromDate", "2018-01-01")
+	query.Set("language", "en & sv")
+
+	endpoint, err := NewEndpoint("/accounts/{{accountId}}/transactions").With("accountId", "1").WithQuery(query).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := "/accounts/1/transactions?fromDate=2018-01-01&language=en+%26+sv"
+	if endpoint != valid {
+		t.Errorf("Build was incorrect, got: %s, want: %s.", endpoint, valid)
+	}
+}
+
+func TestEndpointBuildMissingParameter(t *testing.T) {
+	if _, err := NewEndpoint("/accounts/{{accountId}}").Build(); err == nil {
+		t.Errorf("Build was incorrect, expected an error for a missing parameter.")
+	}
+	if _, err := NewEndpoint("/accounts/{{accountId}}").With("accountId", "").Build(); err == nil {
+		t.Errorf("Build was incorrect, expected an error for an empty parameter.")
+	}
+	if _, err := NewEndpoint("/accounts").With("accountId", "1").Build(); err == nil {
+		t.Errorf("Build was incorrect, expected an error for an unknown parameter.")
+	}
+}
+
+func TestGetFullURL(t *testing.T) {
+	c := InitClient("client-id", "client-secret", "https://httpbin.org/get")
+
+	tests := []struct {
+		endpoint string
+		valid    string
+	}{
+		{"/accounts/%2E%2E", "https://api.nordeaopenbanking.com/v2/accounts/%2E%2E"},
+		{"accounts?continuationKey=a%2Fb", "https://api.nordeaopenbanking.com/v2/accounts?continuationKey=a%2Fb"},
+		{"https://api.nordeaopenbanking.com/v2/accounts/1", "https://api.nordeaopenbanking.com/v2/accounts/1"},
+	}
+
+	for _, test := range tests {
+		if fullURL := c.GetFullURL(test.endpoint); fullURL != test.valid {
+			t.Errorf("GetFullURL was incorrect, got: %s, want: %s.", fullURL, test.valid)
+		}
+	}
+}
+
+func TestRequestRejectsForeignURL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request was incorrect, got: a request with Authorization %s to the foreign host.", r.Header.Get("Authorization"))
+	}))
+	defer foreign.Close()
+
+	c := testClient(server)
+	c.AccessToken = "token"
+	if _, err := c.GetWithAccessToken(foreign.URL+"/v2/accounts", nil); !errors.Is(err, ErrForeignLink) {
+		t.Errorf("GetWithAccessToken was incorrect, got: %v, want: %v.", err, ErrForeignLink)
+	}
+	if _, err := c.GetWithAccessToken("https"+server.URL[len("http"):]+"/v2/accounts", nil); !errors.Is(err, ErrForeignLink) {
+		t.Errorf("GetWithAccessToken was incorrect, got: %v, want: %v for another scheme.", err, ErrForeignLink)
+	}
+
+	response, err := c.GetWithAccessToken(server.URL+"/v2/accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if requests != 1 {
+		t.Errorf("requests were incorrect, got: %d, want: 1 request to the base url.", requests)
+	}
+}
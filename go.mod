@@ -0,0 +1,5 @@
+module github.com/markustenghamn/nordeago
+
+go 1.24
+
+require github.com/google/go-querystring v1.2.0
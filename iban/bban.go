@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package iban
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateBBAN validates a national account number for Sweden (SE), Finland (FI), Denmark (DK) or Norway (NO).
+// Swedish account numbers are validated against the clearing number ranges of the banks.
+func ValidateBBAN(country string, bban string) error {
+	digits := digitsOnly(bban)
+	if len(digits) == 0 || len(strings.Trim(bban, "0123456789 -.,")) > 0 {
+		return fmt.Errorf("bban %s contains invalid characters", bban)
+	}
+
+	switch strings.ToUpper(country) {
+	case "SE":
+		_, err := ParseSwedishAccount(bban)
+		return err
+	case "FI":
+		if !strings.Contains(bban, "-") && len(digits) != 14 && len(digits) < 8 {
+			return fmt.Errorf("finnish bban %s is too short", bban)
+		}
+		machine := finnishMachineFormat(digits)
+		if len(machine) != 14 {
+			return fmt.Errorf("finnish bban %s has invalid length", bban)
+		}
+		if !luhn(machine) {
+			return fmt.Errorf("finnish bban %s has an invalid check digit", bban)
+		}
+	case "DK":
+		if len(digits) < 5 || len(digits) > 14 {
+			return fmt.Errorf("danish bban %s must be a 4 digit registration number and an account number of up to 10 digits", bban)
+		}
+	case "NO":
+		if len(digits) != 11 {
+			return fmt.Errorf("norwegian bban %s must have 11 digits", bban)
+		}
+		if !norwegianMod11(digits) {
+			return fmt.Errorf("norwegian bban %s has an invalid check digit", bban)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrConversionNotSupported, country)
+	}
+	return nil
+}
+
+// finnishMachineFormat converts a Finnish account number such as 123456-785 to the 14 digit machine format. Zeros are
+// added after the 7th digit for savings banks and cooperative banks (starting with 4 or 5) and after the 6th digit
+// for all other banks.
+func finnishMachineFormat(digits string) string {
+	if len(digits) >= 14 || len(digits) < 7 {
+		return digits
+	}
+	padding := strings.Repeat("0", 14-len(digits))
+	if digits[0] == '4' || digits[0] == '5' {
+		return digits[:7] + padding + digits[7:]
+	}
+	return digits[:6] + padding + digits[6:]
+}
+
+// danishAccount pads the account number after the 4 digit registration number to 10 digits
+func danishAccount(digits string) string {
+	return digits[:4] + strings.Repeat("0", 14-len(digits)) + digits[4:]
+}
+
+// luhn validates a number where the last digit is a mod 10 check digit
+func luhn(digits string) bool {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// mod11 validates a number where the last digit is a mod 11 check digit using the weights 1 to 10 from the right
+func mod11(digits string) bool {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[len(digits)-1-i]-'0') * (i%10 + 1)
+	}
+	return sum%11 == 0
+}
+
+func norwegianMod11(digits string) bool {
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+	check := 11 - sum%11
+	if check == 11 {
+		check = 0
+	}
+	return check != 10 && check == int(digits[10]-'0')
+}
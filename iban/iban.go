@@ -0,0 +1,216 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package iban validates IBANs and the national account numbers (BBAN) used in the Nordic countries, and converts
+// between the two where possible.
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrConversionNotSupported is returned when an account number can not be converted between BBAN and IBAN
+var ErrConversionNotSupported = errors.New("conversion not supported")
+
+// countryLengths contains the length of IBANs per country according to the SWIFT IBAN registry
+var countryLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27, "BR": 29,
+	"BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29,
+	"ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28,
+	"HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28,
+	"LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20,
+	"MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28, "PS": 29, "PT": 25,
+	"QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27,
+	"SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// Normalize removes spaces and dashes and converts the account number to upper case
+func Normalize(accountNumber string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "\t", "").Replace(accountNumber))
+}
+
+// Validate checks the country, length, characters and mod-97 check digits of an IBAN. For Nordic countries the
+// national account number is validated as well.
+func Validate(iban string) error {
+	iban = Normalize(iban)
+
+	if len(iban) < 4 {
+		return fmt.Errorf("iban %s is too short", iban)
+	}
+
+	country := iban[:2]
+	length, ok := countryLengths[country]
+	if !ok {
+		return fmt.Errorf("iban %s has unknown country code %s", iban, country)
+	}
+	if len(iban) != length {
+		return fmt.Errorf("iban %s has length %d, %s ibans have length %d", iban, len(iban), country, length)
+	}
+	if !isDigits(iban[2:4]) {
+		return fmt.Errorf("iban %s has invalid check digits", iban)
+	}
+	for _, r := range iban[4:] {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return fmt.Errorf("iban %s contains invalid character %q", iban, r)
+		}
+	}
+
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return fmt.Errorf("iban %s has invalid check digits", iban)
+	}
+
+	return validateNationalPart(country, iban[4:])
+}
+
+// FromBBAN converts a national account number to an IBAN, ErrConversionNotSupported is returned for countries and
+// banks where the conversion is not known
+func FromBBAN(country string, bban string) (string, error) {
+	country = strings.ToUpper(country)
+	if err := ValidateBBAN(country, bban); err != nil {
+		return "", err
+	}
+
+	var national string
+	switch country {
+	case "SE":
+		var err error
+		national, err = swedishIBANPart(bban)
+		if err != nil {
+			return "", err
+		}
+	case "FI":
+		national = finnishMachineFormat(digitsOnly(bban))
+	case "DK":
+		national = danishAccount(digitsOnly(bban))
+	case "NO":
+		national = digitsOnly(bban)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrConversionNotSupported, country)
+	}
+
+	return country + checkDigits(country, national) + national, nil
+}
+
+// ToBBAN converts an IBAN to the national account number, ErrConversionNotSupported is returned for countries and
+// banks where the conversion is not known
+func ToBBAN(iban string) (string, error) {
+	if err := Validate(iban); err != nil {
+		return "", err
+	}
+	iban = Normalize(iban)
+	country, national := iban[:2], iban[4:]
+
+	switch country {
+	case "SE":
+		return swedishBBANFromIBAN(national)
+	case "FI", "DK", "NO":
+		return national, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrConversionNotSupported, country)
+}
+
+// ValidateAccount validates an account number by the _type used in the Nordea API: IBAN, BBAN_SE, BBAN_FI, BBAN_DK or
+// BBAN_NO. Other types such as bankgiro (BGNR) and plusgiro (PGNR) numbers are not validated.
+func ValidateAccount(accountType string, value string) error {
+	switch accountType = strings.ToUpper(accountType); {
+	case accountType == "IBAN":
+		return Validate(value)
+	case strings.HasPrefix(accountType, "BBAN_"):
+		country := strings.TrimPrefix(accountType, "BBAN_")
+		if _, ok := countryLengths[country]; !ok {
+			return nil
+		}
+		err := ValidateBBAN(country, value)
+		if errors.Is(err, ErrConversionNotSupported) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Format returns the IBAN in groups of four characters as it is usually printed
+func Format(iban string) string {
+	iban = Normalize(iban)
+	var groups []string
+	for len(iban) > 4 {
+		groups = append(groups, iban[:4])
+		iban = iban[4:]
+	}
+	return strings.Join(append(groups, iban), " ")
+}
+
+func validateNationalPart(country string, national string) error {
+	switch country {
+	case "SE":
+		_, err := swedishBBANFromIBAN(national)
+		if errors.Is(err, ErrConversionNotSupported) {
+			return nil
+		}
+		return err
+	case "FI", "NO":
+		return ValidateBBAN(country, national)
+	}
+	return nil
+}
+
+// checkDigits calculates the two IBAN check digits for the national part of an account
+func checkDigits(country string, national string) string {
+	return fmt.Sprintf("%02d", 98-mod97(national+country+"00"))
+}
+
+// mod97 calculates the remainder of the IBAN converted to an integer where letters are replaced by two digits
+func mod97(value string) int64 {
+	var digits strings.Builder
+	for _, r := range value {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64()
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// digitsOnly removes all separators from a national account number
+func digitsOnly(s string) string {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
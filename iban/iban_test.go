@@ -0,0 +1,157 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package iban
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"SE45 5000 0000 0583 9825 7466",
+		"FI2112345600000785",
+		"NO9386011117947",
+		"DK5000400440116243",
+		"GB82 WEST 1234 5698 7654 32",
+	}
+	for _, iban := range valid {
+		if err := Validate(iban); err != nil {
+			t.Errorf("Validate was incorrect for %s, got: %s, want: nil.", iban, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"SE45 5000 0000 0583 9825 7467",
+		"SE45 5000 0000 0583 9825 746",
+		"XX45 5000 0000 0583 9825 7466",
+		"NO9386011117948",
+		"GB82 WEST 1234 5698 7654 3!",
+	}
+	for _, iban := range invalid {
+		if err := Validate(iban); err == nil {
+			t.Errorf("Validate was incorrect for %s, got: nil, want: error.", iban)
+		}
+	}
+}
+
+func TestValidateBBAN(t *testing.T) {
+	tests := []struct {
+		country string
+		bban    string
+		valid   bool
+	}{
+		{"SE", "5839-8257466", true},
+		{"SE", "5839-8257467", false},
+		{"SE", "4177 0042136", true},
+		{"SE", "3300 800101-1231", true},
+		{"SE", "3300 800101-1239", false},
+		{"SE", "6789 123456789", true},
+		{"SE", "6789 12345678", false},
+		{"SE", "8327-9 123456782", true},
+		{"SE", "9500 1234566", true},
+		{"SE", "0100 1234567", false},
+		{"FI", "123456-785", true},
+		{"FI", "123456-786", false},
+		{"DK", "0040 0440116243", true},
+		{"DK", "0040", false},
+		{"NO", "8601.11.17947", true},
+		{"NO", "8601.11.17948", false},
+		{"DE", "370400440532013000", false},
+	}
+	for _, test := range tests {
+		err := ValidateBBAN(test.country, test.bban)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateBBAN was incorrect for %s %s, got: %v, want valid: %t.", test.country, test.bban, err, test.valid)
+		}
+	}
+}
+
+func TestFromBBAN(t *testing.T) {
+	tests := []struct {
+		country string
+		bban    string
+		iban    string
+	}{
+		{"SE", "5839-8257466", "SE4550000000058398257466"},
+		{"SE", "3300 800101-1231", "SE3930000000008001011231"},
+		{"SE", "6789 123456789", "SE7160000000000123456789"},
+		{"SE", "8327-9 123456782", "SE6180000083279123456782"},
+		{"FI", "123456-785", "FI2112345600000785"},
+		{"DK", "0040 0440116243", "DK5000400440116243"},
+		{"NO", "8601.11.17947", "NO9386011117947"},
+	}
+	for _, test := range tests {
+		iban, err := FromBBAN(test.country, test.bban)
+		if err != nil {
+			t.Errorf("FromBBAN returned an error for %s %s: %s", test.country, test.bban, err)
+			continue
+		}
+		if iban != test.iban {
+			t.Errorf("FromBBAN was incorrect, got: %s, want: %s.", iban, test.iban)
+		}
+		if err := Validate(iban); err != nil {
+			t.Errorf("FromBBAN returned an invalid iban %s: %s", iban, err)
+		}
+	}
+
+	if _, err := FromBBAN("SE", "9180 1234567897"); !errors.Is(err, ErrConversionNotSupported) {
+		t.Errorf("FromBBAN was incorrect, got: %v, want: %s.", err, ErrConversionNotSupported)
+	}
+}
+
+func TestToBBAN(t *testing.T) {
+	tests := []struct {
+		iban string
+		bban string
+	}{
+		{"SE4550000000058398257466", "58398257466"},
+		{"SE3930000000008001011231", "33008001011231"},
+		{"SE6180000083279123456782", "83279123456782"},
+		{"FI2112345600000785", "12345600000785"},
+		{"NO9386011117947", "86011117947"},
+	}
+	for _, test := range tests {
+		bban, err := ToBBAN(test.iban)
+		if err != nil {
+			t.Errorf("ToBBAN returned an error for %s: %s", test.iban, err)
+			continue
+		}
+		if bban != test.bban {
+			t.Errorf("ToBBAN was incorrect, got: %s, want: %s.", bban, test.bban)
+		}
+	}
+
+	if _, err := ToBBAN("SE7160000000000123456789"); !errors.Is(err, ErrConversionNotSupported) {
+		t.Errorf("ToBBAN was incorrect, got: %v, want: %s.", err, ErrConversionNotSupported)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format("se4550000000058398257466")
+	want := "SE45 5000 0000 0583 9825 7466"
+	if got != want {
+		t.Errorf("Format was incorrect, got: %s, want: %s.", got, want)
+	}
+}
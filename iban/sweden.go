@@ -0,0 +1,252 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package iban
+
+import (
+	"fmt"
+	"strings"
+)
+
+// swedishAccountType describes how the account number and check digit of a Swedish bank account is constructed
+// according to the Bankgirot document "Bankernas kontonummeruppbyggnad"
+type swedishAccountType int
+
+const (
+	// type1Comment1 accounts have 7 digits and the check digit covers the last 3 digits of the clearing number
+	type1Comment1 swedishAccountType = iota
+	// type1Comment2 accounts have 7 digits and the check digit covers the whole clearing number
+	type1Comment2
+	// type2Comment1 accounts have 10 digits with a mod 10 check digit
+	type2Comment1
+	// type2Comment2 accounts have 9 digits with a mod 11 check digit
+	type2Comment2
+	// type2Comment3 accounts have up to 10 digits with a mod 10 check digit
+	type2Comment3
+)
+
+type swedishBank struct {
+	name        string
+	from, to    int
+	accountType swedishAccountType
+	// ibanCode is the bank identifier used in IBANs, empty if the conversion is not known
+	ibanCode string
+}
+
+// swedishBanks contains the clearing number ranges of the Swedish banks
+var swedishBanks = []swedishBank{
+	{"Svenska Handelsbanken", 6000, 6999, type2Comment2, "600"},
+	{"Nordea", 1100, 1199, type1Comment1, "300"},
+	{"Danske Bank", 1200, 1399, type1Comment1, "120"},
+	{"Nordea", 1400, 2099, type1Comment1, "300"},
+	{"Ålandsbanken", 2300, 2399, type1Comment2, "230"},
+	{"Nordea", 3000, 3299, type1Comment1, "300"},
+	{"Nordea Personkonto", 3300, 3300, type2Comment1, "300"},
+	{"Nordea", 3301, 3399, type1Comment1, "300"},
+	{"Länsförsäkringar Bank", 3400, 3409, type1Comment1, "902"},
+	{"Nordea", 3410, 3781, type1Comment1, "300"},
+	{"Nordea Personkonto", 3782, 3782, type2Comment1, "300"},
+	{"Nordea", 3783, 3999, type1Comment1, "300"},
+	{"Nordea", 4000, 4999, type1Comment2, "300"},
+	{"SEB", 5000, 5999, type1Comment1, "500"},
+	{"Swedbank", 7000, 7999, type1Comment1, "800"},
+	{"Swedbank", 8000, 8999, type2Comment3, "800"},
+	{"Länsförsäkringar Bank", 9020, 9029, type1Comment2, "902"},
+	{"Citibank", 9040, 9049, type1Comment2, "904"},
+	{"Länsförsäkringar Bank", 9060, 9069, type1Comment1, "902"},
+	{"Nordnet Bank", 9100, 9109, type1Comment2, "910"},
+	{"SEB", 9120, 9124, type1Comment1, "500"},
+	{"SEB", 9130, 9149, type1Comment1, "500"},
+	{"Skandiabanken", 9150, 9169, type1Comment2, "915"},
+	{"Ikano Bank", 9170, 9179, type1Comment1, "917"},
+	{"Danske Bank", 9180, 9189, type2Comment1, ""},
+	{"DNB Bank", 9190, 9199, type1Comment2, "919"},
+	{"Marginalen Bank", 9230, 9239, type1Comment1, "923"},
+	{"SBAB Bank", 9250, 9259, type1Comment1, "925"},
+	{"ICA Banken", 9270, 9279, type1Comment1, "927"},
+	{"Resurs Bank", 9280, 9289, type1Comment1, "928"},
+	{"Landshypotek", 9390, 9399, type1Comment2, "939"},
+	{"Forex Bank", 9400, 9449, type1Comment1, "940"},
+	{"Santander Consumer Bank", 9460, 9469, type1Comment1, "946"},
+	{"BNP Paribas", 9470, 9479, type1Comment2, "947"},
+	{"Nordea Plusgirot", 9500, 9549, type2Comment3, "950"},
+	{"Avanza Bank", 9550, 9569, type1Comment2, "955"},
+	{"Sparbanken Syd", 9570, 9579, type2Comment1, ""},
+	{"Erik Penser", 9590, 9599, type1Comment1, "959"},
+	{"Lån & Spar Bank", 9630, 9639, type1Comment1, "963"},
+	{"Nordax Bank", 9640, 9649, type1Comment2, "964"},
+	{"Svea Bank", 9660, 9669, type1Comment2, "966"},
+	{"JAK Medlemsbank", 9670, 9679, type1Comment2, "967"},
+	{"Bluestep Finans", 9680, 9689, type1Comment1, "968"},
+	{"Ekobanken", 9700, 9709, type1Comment2, "970"},
+	{"Riksgälden", 9880, 9889, type1Comment2, "988"},
+	{"Riksgälden", 9890, 9899, type2Comment1, ""},
+	{"Nordea Plusgirot", 9960, 9969, type2Comment3, "950"},
+}
+
+// SwedishAccount is a Swedish bank account split into clearing number and account number
+type SwedishAccount struct {
+	Bank     string
+	Clearing string
+	Account  string
+}
+
+// String returns the clearing number followed by the account number
+func (a SwedishAccount) String() string {
+	return a.Clearing + a.Account
+}
+
+// ParseSwedishAccount splits a Swedish account number into clearing number and account number, finds the bank
+// from the clearing number and validates the length and check digit of the account number
+func ParseSwedishAccount(bban string) (SwedishAccount, error) {
+	digits := digitsOnly(bban)
+	if len(digits) < 5 {
+		return SwedishAccount{}, fmt.Errorf("swedish bban %s is too short", bban)
+	}
+
+	bank, ok := findSwedishBank(digits[:4])
+	if !ok {
+		return SwedishAccount{}, fmt.Errorf("swedish bban %s has unknown clearing number %s", bban, digits[:4])
+	}
+
+	clearingLength := 4
+	if digits[0] == '8' {
+		// Swedbank clearing numbers starting with 8 have a fifth check digit
+		clearingLength = 5
+	}
+	if len(digits) <= clearingLength {
+		return SwedishAccount{}, fmt.Errorf("swedish bban %s is too short", bban)
+	}
+
+	account := SwedishAccount{
+		Bank:     bank.name,
+		Clearing: digits[:clearingLength],
+		Account:  digits[clearingLength:],
+	}
+	return account, account.validate(bank)
+}
+
+func (a SwedishAccount) validate(bank swedishBank) error {
+	switch bank.accountType {
+	case type1Comment1, type1Comment2:
+		if len(a.Account) != 7 {
+			return fmt.Errorf("swedish account %s at %s must have 7 digits", a.Account, bank.name)
+		}
+		number := a.Clearing + a.Account
+		if bank.accountType == type1Comment1 {
+			number = number[1:]
+		}
+		if !mod11(number) {
+			return fmt.Errorf("swedish account %s at %s has an invalid check digit", a.Account, bank.name)
+		}
+	case type2Comment1:
+		if len(a.Account) != 10 {
+			return fmt.Errorf("swedish account %s at %s must have 10 digits", a.Account, bank.name)
+		}
+		if !luhn(a.Account) {
+			return fmt.Errorf("swedish account %s at %s has an invalid check digit", a.Account, bank.name)
+		}
+	case type2Comment2:
+		if len(a.Account) != 9 {
+			return fmt.Errorf("swedish account %s at %s must have 9 digits", a.Account, bank.name)
+		}
+		if !mod11(a.Account) {
+			return fmt.Errorf("swedish account %s at %s has an invalid check digit", a.Account, bank.name)
+		}
+	case type2Comment3:
+		if len(a.Account) > 10 {
+			return fmt.Errorf("swedish account %s at %s must have at most 10 digits", a.Account, bank.name)
+		}
+		if !luhn(a.Account) {
+			return fmt.Errorf("swedish account %s at %s has an invalid check digit", a.Account, bank.name)
+		}
+	}
+	return nil
+}
+
+func findSwedishBank(clearing string) (swedishBank, bool) {
+	var number int
+	if _, err := fmt.Sscanf(clearing, "%4d", &number); err != nil {
+		return swedishBank{}, false
+	}
+	for _, bank := range swedishBanks {
+		if number >= bank.from && number <= bank.to {
+			return bank, true
+		}
+	}
+	return swedishBank{}, false
+}
+
+// swedishIBANPart returns the 20 digits following the check digits in a Swedish IBAN. The clearing number is part
+// of the IBAN except for Handelsbanken, Nordea Personkonto and Plusgirot accounts.
+func swedishIBANPart(bban string) (string, error) {
+	account, err := ParseSwedishAccount(bban)
+	if err != nil {
+		return "", err
+	}
+	bank, _ := findSwedishBank(account.Clearing[:4])
+	if len(bank.ibanCode) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrConversionNotSupported, bank.name)
+	}
+
+	number := account.Clearing + account.Account
+	if bank.accountType == type2Comment1 || bank.accountType == type2Comment2 || bank.from == 9500 || bank.from == 9960 {
+		number = account.Account
+	}
+	return bank.ibanCode + strings.Repeat("0", 17-len(number)) + number, nil
+}
+
+// swedishBBANFromIBAN returns the clearing number and account number from the 20 digits following the check digits
+// in a Swedish IBAN
+func swedishBBANFromIBAN(national string) (string, error) {
+	if len(national) != 20 || !isDigits(national) {
+		return "", fmt.Errorf("swedish iban part %s must have 20 digits", national)
+	}
+	code, number := national[:3], strings.TrimLeft(national[3:], "0")
+
+	var bban string
+	switch code {
+	case "600":
+		// Handelsbanken accounts can be used with any clearing number in the range
+		return "", fmt.Errorf("%w: clearing number is not part of Handelsbanken ibans", ErrConversionNotSupported)
+	case "950":
+		bban = "9500" + number
+	case "300":
+		// Nordea clearing numbers never start with 0, a leading 0 means a 10 digit personkonto
+		if national[9] == '0' {
+			bban = "3300" + national[10:]
+		} else {
+			bban = number
+		}
+	default:
+		bban = number
+	}
+
+	account, err := ParseSwedishAccount(bban)
+	if err != nil {
+		return "", err
+	}
+	if bank, _ := findSwedishBank(account.Clearing[:4]); bank.ibanCode != code {
+		return "", fmt.Errorf("swedish iban part %s has clearing number %s which does not belong to bank %s", national, account.Clearing, code)
+	}
+	return account.String(), nil
+}
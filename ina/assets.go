@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ina
+
+import "github.com/markustenghamn/nordeago/ais"
+
+// Scopes that can be requested in AuthRequestDecoupled and AuthRequest and that are returned by GetAssets
+const (
+	ScopeAccountsBasic        = "ACCOUNTS_BASIC"
+	ScopeAccountsBalances     = "ACCOUNTS_BALANCES"
+	ScopeAccountsDetails      = "ACCOUNTS_DETAILS"
+	ScopeAccountsTransactions = "ACCOUNTS_TRANSACTIONS"
+	ScopePaymentsMultiple     = "PAYMENTS_MULTIPLE"
+	ScopePaymentsSingle       = "PAYMENTS_SINGLE"
+)
+
+// AccountConsent links a single account returned by GetAssets to the scopes that were granted for it
+type AccountConsent struct {
+	Account ais.Account
+	Scopes  []string
+}
+
+// HasScope returns true if the scope was granted for the account
+func (a AccountConsent) HasScope(scope string) bool {
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CanListTransactions returns true if transactions can be fetched for the account via ais.GetAccountTransactions
+func (a AccountConsent) CanListTransactions() bool {
+	return a.HasScope(ScopeAccountsTransactions)
+}
+
+// CanReadBalances returns true if balances can be read for the account
+func (a AccountConsent) CanReadBalances() bool {
+	return a.HasScope(ScopeAccountsBalances)
+}
+
+// CanInitiatePayments returns true if payments can be initiated from the account via pis.InitiatePayment
+func (a AccountConsent) CanInitiatePayments() bool {
+	return a.HasScope(ScopePaymentsMultiple) || a.HasScope(ScopePaymentsSingle)
+}
+
+// Consents maps each granted account to the scopes of the consent. The Nordea API grants the same scopes to every
+// account in a consent so each AccountConsent gets a copy of the returned scopes
+func (r *AssetsResponse) Consents() []AccountConsent {
+	consents := make([]AccountConsent, 0, len(r.Accounts))
+	for _, account := range r.Accounts {
+		scopes := make([]string, len(r.Scopes))
+		copy(scopes, r.Scopes)
+		consents = append(consents, AccountConsent{Account: account, Scopes: scopes})
+	}
+	return consents
+}
+
+// Consent returns the AccountConsent for the specified account id, the second return value is false if the account
+// is not part of the consent
+func (r *AssetsResponse) Consent(accountID string) (AccountConsent, bool) {
+	for _, consent := range r.Consents() {
+		if consent.Account.AccountID == accountID {
+			return consent, true
+		}
+	}
+	return AccountConsent{}, false
+}
+
+// AccountsWithScope returns all accounts where the specified scope has been granted
+func (r *AssetsResponse) AccountsWithScope(scope string) []ais.Account {
+	var accounts []ais.Account
+	for _, consent := range r.Consents() {
+		if consent.HasScope(scope) {
+			accounts = append(accounts, consent.Account)
+		}
+	}
+	return accounts
+}
@@ -0,0 +1,53 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ina
+
+import (
+	"testing"
+
+	"github.com/markustenghamn/nordeago/ais"
+)
+
+func TestAssetsResponseConsents(t *testing.T) {
+	assets := AssetsResponse{
+		Accounts: []ais.Account{{AccountID: "FI6593857450293470-EUR"}, {AccountID: "41770042136"}},
+		Scopes:   []string{ScopeAccountsBasic, ScopeAccountsTransactions},
+	}
+
+	consent, ok := assets.Consent("41770042136")
+	if !ok {
+		t.Fatalf("Consent did not find account 41770042136")
+	}
+	if !consent.CanListTransactions() {
+		t.Errorf("CanListTransactions was incorrect, got: false, want: true.")
+	}
+	if consent.CanReadBalances() || consent.CanInitiatePayments() {
+		t.Errorf("CanReadBalances or CanInitiatePayments was incorrect, got: true, want: false.")
+	}
+	if _, ok := assets.Consent("missing"); ok {
+		t.Errorf("Consent was incorrect, found an account that is not part of the consent.")
+	}
+	if accounts := assets.AccountsWithScope(ScopeAccountsTransactions); len(accounts) != 2 {
+		t.Errorf("AccountsWithScope was incorrect, got: %d accounts, want: 2.", len(accounts))
+	}
+}
@@ -23,11 +23,11 @@
 package ina
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
+	"net/http"
+
 	"github.com/google/go-querystring/query"
 	"github.com/markustenghamn/nordeago"
-	"net/http"
 )
 
 // StartAuthDecoupled initiates authentication with the Nordea API which will allow us to make requests to the Accounts and Payments API.
@@ -35,30 +35,20 @@ import (
 // Warning: Decoupled Authorisation flow is a mock version, and it is only intended to show how the production version will work.
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#authorize
-func StartAuthDecoupled(c *nordeago.Client, request AuthRequestDecoupled) (*Response, error) {
-	responseType := &Response{}
-	result := nordeago.Result{Response: responseType}
-	endpoint := "/authorize-decoupled"
-
-	headers := make(map[string]string)
-	headers["X-IBM-Client-Id"] = c.ClientID
-	headers["X-IBM-Client-Secret"] = c.ClientSecret
-
-	response, err := c.Post(endpoint, request, headers)
-
-	if err != nil {
-		return responseType, err
-	}
-
-	defer response.Body.Close()
-
-	_, err = c.HandleResponse(response, &result)
+func StartAuthDecoupled(ctx context.Context, c *nordeago.Client, request AuthRequestDecoupled) (*Response, error) {
+	response, _, err := nordeago.DoRequest[Response](ctx, c, nordeago.Request{
+		Method:          http.MethodPost,
+		Endpoint:        "/authorize-decoupled",
+		Body:            request,
+		Headers:         clientHeaders(c, ""),
+		SkipAccessToken: true,
+	})
 
 	if err == nil {
-		c.TppToken = responseType.TppToken
+		c.TppToken = response.TppToken
 	}
 
-	return responseType, err
+	return &response, err
 }
 
 // PollForAuthCodeDecoupled polls for an auth code which will be returned when the user has accepted access to
@@ -66,74 +56,32 @@ func StartAuthDecoupled(c *nordeago.Client, request AuthRequestDecoupled) (*Resp
 // Warning: Decoupled Authorisation flow is a mock version, and it is only intended to show how the production version will work.
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#getToken
-func PollForAuthCodeDecoupled(c *nordeago.Client, orderRef string) (*Response, int, error) {
-	responseType := &Response{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := "/authorize-decoupled/{{order_ref}}"
-	endpoint = nordeago.ReplaceVariable(endpoint, "order_ref", orderRef)
-
-	headers := make(map[string]string)
-	headers["Authorization"] = nordeago.BearerAuthHeader(c.TppToken)
-	headers["X-IBM-Client-Id"] = c.ClientID
-	headers["X-IBM-Client-Secret"] = c.ClientSecret
-
-	response, err := c.Get(endpoint, headers)
-
+func PollForAuthCodeDecoupled(ctx context.Context, c *nordeago.Client, orderRef string) (*Response, int, error) {
+	endpoint, err := nordeago.NewEndpoint("/authorize-decoupled/{{order_ref}}").With("order_ref", orderRef).Build()
 	if err != nil {
-		return responseType, response.StatusCode, err
+		return &Response{}, 0, err
 	}
 
-	defer response.Body.Close()
-
-	statusCode, err := c.HandleResponse(response, &result)
+	response, meta, err := nordeago.DoRequest[Response](ctx, c, nordeago.Request{
+		Method:          http.MethodGet,
+		Endpoint:        endpoint,
+		Headers:         clientHeaders(c, c.TppToken),
+		SkipAccessToken: true,
+	})
 
 	if err == nil {
-		c.AuthCode = responseType.Code
+		c.AuthCode = response.Code
 	}
 
-	return responseType, statusCode, err
+	return &response, meta.StatusCode, err
 }
 
 // RetrieveAccessTokenDecoupled returns a bearer token to use for the Accounts and Payments API requests.
 // Warning: Decoupled Authorisation flow is a mock version, and it is only intended to show how the production version will work.
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#getToken
-func RetrieveAccessTokenDecoupled(c *nordeago.Client, request RetrieveAccessTokenRequest) (RetrieveAccessTokenResponse, error) {
-	var retrieveAccessTokenResponse RetrieveAccessTokenResponse
-	result := nordeago.Result{Response: &retrieveAccessTokenResponse}
-
-	endpoint := "/authorize-decoupled/token"
-
-	headers := make(map[string]string)
-	headers["Authorization"] = nordeago.BearerAuthHeader(c.TppToken)
-	headers["X-IBM-Client-Id"] = c.ClientID
-	headers["X-IBM-Client-Secret"] = c.ClientSecret
-
-	response, err := c.Post(endpoint, request, headers)
-
-	if err != nil {
-		return retrieveAccessTokenResponse, err
-	}
-
-	defer response.Body.Close()
-
-	decoder := json.NewDecoder(response.Body)
-
-	if response.StatusCode == http.StatusOK {
-		err = decoder.Decode(&retrieveAccessTokenResponse)
-
-		if err != nil {
-			return retrieveAccessTokenResponse, err
-		}
-
-		c.AccessToken = retrieveAccessTokenResponse.AccessToken
-	} else {
-		_, err = c.HandleResponse(response, &result)
-		return retrieveAccessTokenResponse, err
-	}
-
-	return retrieveAccessTokenResponse, nil
+func RetrieveAccessTokenDecoupled(ctx context.Context, c *nordeago.Client, request RetrieveAccessTokenRequest) (RetrieveAccessTokenResponse, error) {
+	return retrieveAccessToken(ctx, c, "/authorize-decoupled/token", request, c.TppToken)
 }
 
 // StartAuth returns a url to redirect the user to for Oauth flow?
@@ -142,18 +90,17 @@ func RetrieveAccessTokenDecoupled(c *nordeago.Client, request RetrieveAccessToke
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#startAuthentication
 func StartAuth(c *nordeago.Client, request AuthRequest) (string, error) {
-	endpoint := "/authorize"
-
-	req, err := http.NewRequest("GET", c.GetFullURL(endpoint), nil)
+	v, err := query.Values(request)
 	if err != nil {
-		return endpoint, err
+		return "", err
 	}
 
-	v, _ := query.Values(request)
-
-	req.URL.RawQuery = v.Encode()
+	endpoint, err := nordeago.NewEndpoint("/authorize").WithQuery(v).Build()
+	if err != nil {
+		return "", err
+	}
 
-	return req.URL.String(), nil
+	return c.GetFullURL(endpoint), nil
 }
 
 // RetrieveAccessToken returns a bearer token to use for the Accounts and Payments API requests.
@@ -161,68 +108,63 @@ func StartAuth(c *nordeago.Client, request AuthRequest) (string, error) {
 // TODO I am not sure how this differs as I do not have access to a production environment and can't test
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#accessToken
-func RetrieveAccessToken(c *nordeago.Client, request RetrieveAccessTokenRequest) (RetrieveAccessTokenResponse, error) {
-	var retrieveAccessTokenResponse RetrieveAccessTokenResponse
-
-	endpoint := "/authorize/access_token"
-
-	headers := make(map[string]string)
-	headers["Authorization"] = nordeago.BearerAuthHeader(c.TppToken)
-	headers["X-IBM-Client-Id"] = c.ClientID
-	headers["X-IBM-Client-Secret"] = c.ClientSecret
-
-	response, err := c.Post(endpoint, request, headers)
-
-	if err != nil {
-		return retrieveAccessTokenResponse, err
-	}
-
-	defer response.Body.Close()
-
-	decoder := json.NewDecoder(response.Body)
-
-	if response.StatusCode == http.StatusOK {
-		err = decoder.Decode(&retrieveAccessTokenResponse)
-
-		if err != nil {
-			return retrieveAccessTokenResponse, err
-		}
-
-		c.AccessToken = retrieveAccessTokenResponse.AccessToken
-	} else {
-		var errorResponse nordeago.ErrorResponse
-		err = decoder.Decode(&errorResponse)
-
-		if err != nil {
-			return retrieveAccessTokenResponse, err
-		}
-
-		err := fmt.Errorf("%d - %s: %s", errorResponse.HTTPCode, errorResponse.HTTPMessage, errorResponse.MoreInformation)
+func RetrieveAccessToken(ctx context.Context, c *nordeago.Client, request RetrieveAccessTokenRequest) (RetrieveAccessTokenResponse, error) {
+	return retrieveAccessToken(ctx, c, "/authorize/access_token", request, c.TppToken)
+}
 
-		return retrieveAccessTokenResponse, err
+// RefreshAccessToken uses the refresh token of the client to retrieve a new access token. Use a TokenRefresher when
+// several processes share the same consent so that they do not invalidate each others tokens.
+//
+// TODO I am not sure how this differs as I do not have access to a production environment and can't test
+//
+// API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#accessToken
+func RefreshAccessToken(ctx context.Context, c *nordeago.Client) (RetrieveAccessTokenResponse, error) {
+	request := RefreshAccessTokenRequest{
+		GrantType:    "refresh_token",
+		RefreshToken: c.RefreshToken,
 	}
 
-	return retrieveAccessTokenResponse, nil
+	return retrieveAccessToken(ctx, c, "/authorize/access_token", request, "")
 }
 
-// GetAssets use an access token to get the assets or accounts of the authenticated user
+// GetAssets use an access token to get the assets or accounts of the authenticated user along with the scopes that
+// were granted for them
 //
 // API Documentation: https://developer.nordeaopenbanking.com/app/documentation?api=Identity%20and%20Access%20API&version=2.1#getAssets
-func GetAssets(c *nordeago.Client) (*Response, error) {
-	responseType := &Response{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := "/assets"
+func GetAssets(ctx context.Context, c *nordeago.Client) (*AssetsResponse, error) {
+	response, _, err := nordeago.Do[AssetsResponse](ctx, c, http.MethodGet, "/assets", nil)
+	return &response, err
+}
 
-	response, err := c.GetWithAccessToken(endpoint, nil)
+// retrieveAccessToken posts a token request and sets the returned tokens on the client
+func retrieveAccessToken(ctx context.Context, c *nordeago.Client, endpoint string, request interface{}, tppToken string) (RetrieveAccessTokenResponse, error) {
+	response, _, err := nordeago.DoRequest[RetrieveAccessTokenResponse](ctx, c, nordeago.Request{
+		Method:          http.MethodPost,
+		Endpoint:        endpoint,
+		Body:            request,
+		Headers:         clientHeaders(c, tppToken),
+		SkipAccessToken: true,
+	})
 
 	if err != nil {
-		return responseType, err
+		return response, err
 	}
 
-	defer response.Body.Close()
+	c.SetAccessToken(response.AccessToken)
+	if len(response.RefreshToken) > 0 {
+		c.RefreshToken = response.RefreshToken
+	}
 
-	_, err = c.HandleResponse(response, &result)
+	return response, nil
+}
 
-	return responseType, err
+// clientHeaders returns the client credential headers and the tpp token as authorization header if it is set
+func clientHeaders(c *nordeago.Client, tppToken string) map[string]string {
+	headers := make(map[string]string)
+	if len(tppToken) > 0 {
+		headers["Authorization"] = nordeago.BearerAuthHeader(tppToken)
+	}
+	headers["X-IBM-Client-Id"] = c.ClientID
+	headers["X-IBM-Client-Secret"] = c.ClientSecret
+	return headers
 }
@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ina
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+var defaultRefreshLocker = &nordeago.MemoryRefreshLocker{}
+
+// TokenRefresher refreshes the access token of a client while holding a lock for the key so that only one replica
+// refreshes the token for a PSU at a time. Replicas that waited for the lock reload the new token from the Store
+// instead of refreshing it again. TokenRefresher can be used as the ReauthHandler of a client.
+type TokenRefresher struct {
+	Key     string // Identifies the PSU or consent, must be the same for all replicas
+	Store   nordeago.TokenStore
+	Locker  nordeago.RefreshLocker // Defaults to an in-process locker
+	Timeout time.Duration          // Maximum time to wait for the lock, defaults to 30 seconds
+}
+
+// Reauthenticate implements nordeago.ReauthHandler
+func (r *TokenRefresher) Reauthenticate(ctx context.Context, c *nordeago.Client) error {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return r.Refresh(ctx, c)
+}
+
+// Refresh sets a valid access token on the client, either by loading a token that another replica refreshed or by
+// calling RefreshAccessToken and saving the result to the Store
+func (r *TokenRefresher) Refresh(ctx context.Context, c *nordeago.Client) error {
+	locker := r.Locker
+	if locker == nil {
+		locker = defaultRefreshLocker
+	}
+
+	rejectedToken := c.GetAccessToken()
+
+	unlock, err := locker.Lock(ctx, r.Key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	token, err := r.Store.Load(r.Key)
+	if err != nil && !errors.Is(err, nordeago.ErrTokenNotFound) {
+		return err
+	}
+
+	// Another replica refreshed the token while we were waiting for the lock
+	if err == nil && token.AccessToken != rejectedToken && !token.Expired() {
+		c.SetAccessToken(token.AccessToken)
+		c.RefreshToken = token.RefreshToken
+		return nil
+	}
+
+	if len(token.RefreshToken) > 0 {
+		c.RefreshToken = token.RefreshToken
+	}
+	if len(c.RefreshToken) == 0 {
+		return errors.New("no refresh token available, the authentication flow has to be restarted")
+	}
+
+	response, err := RefreshAccessToken(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	// Keep the metadata of the stored token as it is needed to inspect opaque tokens
+	refreshed := response.Token()
+	refreshed.RefreshToken = c.RefreshToken
+	refreshed.Scopes = token.Scopes
+	refreshed.Accounts = token.Accounts
+	refreshed.PsuID = token.PsuID
+
+	return r.Store.Save(r.Key, refreshed)
+}
@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ina
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func TestTokenRefresherRefreshesOnce(t *testing.T) {
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"access_token":"new-token","expires_in":3600,"token_type":"Bearer","refresh_token":"new-refresh"}`))
+	}))
+	defer server.Close()
+
+	store := &nordeago.MemoryTokenStore{}
+	store.Save("193805010844", nordeago.Token{AccessToken: "old-token", RefreshToken: "old-refresh"})
+	locker := &nordeago.MemoryRefreshLocker{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := nordeago.InitClient("client-id", "client-secret", "https://httpbin.org/get")
+			c.Protocol = "http://"
+			c.BaseURL = strings.TrimPrefix(server.URL, "http://")
+			c.AccessToken = "old-token"
+			refresher := TokenRefresher{Key: "193805010844", Store: store, Locker: locker}
+			if err := refresher.Refresh(context.Background(), &c); err != nil {
+				t.Error(err)
+			}
+			if c.AccessToken != "new-token" {
+				t.Errorf("AccessToken was incorrect, got: %s, want: new-token.", c.AccessToken)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshes != 1 {
+		t.Errorf("refresh count was incorrect, got: %d, want: 1.", refreshes)
+	}
+}
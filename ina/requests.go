@@ -31,3 +31,9 @@ type RetrieveAccessTokenRequest struct {
 	Code        string `json:"code"`
 	RedirectURI string `json:"redirect_uri"`
 }
+
+// RefreshAccessTokenRequest is used with RefreshAccessToken to get a new access token using a refresh token
+type RefreshAccessTokenRequest struct {
+	GrantType    string `json:"grant_type"` // Always refresh_token
+	RefreshToken string `json:"refresh_token"`
+}
@@ -20,7 +20,15 @@ type Response struct {
 
 // RetrieveAccessTokenResponse represents the response returned from PollForAuthCodeDecoupled
 type RetrieveAccessTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int64  `json:"expires_in"`
-	TokenType   string `json:"token_type"` // Always BEARER
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	TokenType    string `json:"token_type"` // Always BEARER
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// AssetsResponse is returned from GetAssets and contains the accounts that the user has given consent to along with
+// the scopes that were granted for those accounts
+type AssetsResponse struct {
+	Accounts []ais.Account `json:"accounts,omitempty"`
+	Scopes   []string      `json:"scopes,omitempty"`
 }
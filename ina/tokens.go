@@ -0,0 +1,48 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ina
+
+import (
+	"time"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+// Token converts the response to a nordeago.Token that can be saved in a TokenStore. The expiry is calculated from
+// ExpiresIn relative to the time Token is called so it should be called right after the token was retrieved.
+func (r RetrieveAccessTokenResponse) Token() nordeago.Token {
+	token := nordeago.Token{
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+	}
+	if r.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return token
+}
+
+// Claims decodes the claims of the access token without verifying it, see nordeago.ParseTokenClaims. Opaque tokens
+// only return the expiry calculated from ExpiresIn, use Token.Claims with stored metadata to get scopes and accounts.
+func (r RetrieveAccessTokenResponse) Claims() (nordeago.TokenClaims, error) {
+	return r.Token().Claims()
+}
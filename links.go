@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrForeignLink is returned when an absolute link points to another scheme or host than the base url of the client.
+// Such links are never followed since the request would carry the access token and client credentials.
+var ErrForeignLink = errors.New("link points outside the api host")
+
+// FindLink returns the first link with the relation rel, the second return value is false if there is no such link
+func FindLink(links []Link, rel string) (Link, bool) {
+	for _, link := range links {
+		if link.Rel == rel {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+// ResolveLink expands the href of a templated link with the variables and resolves it against the base url of the
+// client. Relative hrefs that do not include the API version are resolved relative to the version. Absolute hrefs
+// must use the scheme and host of the base url, otherwise ErrForeignLink is returned.
+func (c *Client) ResolveLink(link Link, variables map[string]interface{}) (string, error) {
+	href := link.Href
+	if link.Templated {
+		var err error
+		href, err = ExpandURITemplate(href, variables)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	reference, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(c.GetFullURL(""))
+	if err != nil {
+		return "", err
+	}
+
+	if reference.IsAbs() || len(reference.Host) > 0 {
+		resolved := base.ResolveReference(reference)
+		if !strings.EqualFold(resolved.Scheme, base.Scheme) || !strings.EqualFold(resolved.Host, base.Host) {
+			return "", fmt.Errorf("%w: %s", ErrForeignLink, resolved.Redacted())
+		}
+		return resolved.String(), nil
+	}
+
+	version := strings.Trim(c.Version, "/")
+	if strings.HasPrefix(href, "/") && len(version) > 0 && !strings.HasPrefix(href, "/"+version+"/") {
+		reference.Path = strings.TrimLeft(reference.Path, "/")
+		if len(reference.RawPath) > 0 {
+			reference.RawPath = strings.TrimLeft(reference.RawPath, "/")
+		}
+	}
+
+	return base.ResolveReference(reference).String(), nil
+}
+
+// Follow makes a GET request to the link using the access token of the client and decodes the response into out,
+// which must be a pointer. Templated links are expanded without variables, use FollowRequest to set them.
+func (c *Client) Follow(ctx context.Context, link Link, out interface{}) error {
+	_, err := c.FollowRequest(ctx, http.MethodGet, link, nil, nil, out)
+	return err
+}
+
+// FollowRequest makes a request to the link with the method and body and decodes the response into out. This can be
+// used for actions such as confirming a payment from its confirm link.
+func (c *Client) FollowRequest(ctx context.Context, method string, link Link, variables map[string]interface{}, body interface{}, out interface{}) (ResponseMeta, error) {
+	if len(link.Href) == 0 {
+		return ResponseMeta{}, fmt.Errorf("link %s has no href", link.Rel)
+	}
+
+	endpoint, err := c.ResolveLink(link, variables)
+	if err != nil {
+		return ResponseMeta{}, err
+	}
+
+	return c.do(ctx, Request{Method: method, Endpoint: endpoint, Body: body}, out)
+}
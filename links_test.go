@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveLink(t *testing.T) {
+	c := InitClient("client-id", "client-secret", "https://httpbin.org/get")
+
+	tests := []struct {
+		link  Link
+		valid string
+	}{
+		{Link{Href: "https://api.nordeaopenbanking.com/v2/accounts/1"}, "https://api.nordeaopenbanking.com/v2/accounts/1"},
+		{Link{Href: "/v2/payments/sepa/1"}, "https://api.nordeaopenbanking.com/v2/payments/sepa/1"},
+		{Link{Href: "/payments/sepa/1/confirm"}, "https://api.nordeaopenbanking.com/v2/payments/sepa/1/confirm"},
+		{Link{Href: "accounts/1/transactions"}, "https://api.nordeaopenbanking.com/v2/accounts/1/transactions"},
+		{Link{Href: "/accounts/{accountId}/transactions{?continuationKey}", Templated: true}, "https://api.nordeaopenbanking.com/v2/accounts/SE%2F1/transactions?continuationKey=a%2Fb"},
+	}
+
+	variables := map[string]interface{}{"accountId": "SE/1", "continuationKey": "a/b"}
+	for _, test := range tests {
+		resolved, err := c.ResolveLink(test.link, variables)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved != test.valid {
+			t.Errorf("ResolveLink(%s) was incorrect, got: %s, want: %s.", test.link.Href, resolved, test.valid)
+		}
+	}
+}
+
+func TestFollow(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.Write([]byte(`{"response":{"paymentStatus":"Confirmed"}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	links := []Link{{Rel: "self", Href: "/v2/payments/sepa/1"}, {Rel: "confirm", Href: "/v2/payments/sepa/1/confirm"}}
+
+	link, ok := FindLink(links, "confirm")
+	if !ok {
+		t.Fatalf("FindLink did not find the confirm link")
+	}
+	if _, ok := FindLink(links, "delete"); ok {
+		t.Errorf("FindLink was incorrect, found a link that does not exist.")
+	}
+
+	var payment struct {
+		PaymentStatus string `json:"paymentStatus"`
+	}
+	if _, err := c.FollowRequest(context.Background(), http.MethodPut, link, nil, nil, &payment); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut || path != "/v2/payments/sepa/1/confirm" || payment.PaymentStatus != "Confirmed" {
+		t.Errorf("FollowRequest was incorrect, got: %s %s %+v.", method, path, payment)
+	}
+
+	if err := c.Follow(context.Background(), links[0], &payment); err != nil || method != http.MethodGet {
+		t.Errorf("Follow was incorrect, got: %s and error %v.", method, err)
+	}
+}
+
+func TestFollowForeignLink(t *testing.T) {
+	var leaked []string
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range []string{"Authorization", "X-IBM-Client-Id", "X-IBM-Client-Secret"} {
+			if len(r.Header.Get(header)) > 0 {
+				leaked = append(leaked, header)
+			}
+		}
+		w.Write([]byte(`{"response":{}}`))
+	}))
+	defer foreign.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	c.AccessToken = "secret-token"
+
+	links := []Link{
+		{Rel: "next", Href: foreign.URL + "/v2/accounts"},
+		{Rel: "next", Href: "//" + strings.TrimPrefix(foreign.URL, "http://") + "/v2/accounts"},
+		{Rel: "next", Href: "https://" + c.BaseURL + "/v2/accounts"},
+	}
+	for _, link := range links {
+		err := c.Follow(context.Background(), link, &struct{}{})
+		if !errors.Is(err, ErrForeignLink) {
+			t.Errorf("Follow(%s) was incorrect, got: %v, want: %v.", link.Href, err, ErrForeignLink)
+		}
+	}
+	if len(leaked) > 0 {
+		t.Errorf("Follow sent credentials to a foreign host, got: %v.", leaked)
+	}
+
+	if err := c.Follow(context.Background(), Link{Href: server.URL + "/v2/accounts"}, &struct{}{}); err != nil {
+		t.Errorf("Follow was incorrect for an absolute link on the api host, got: %v.", err)
+	}
+}
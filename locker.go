@@ -0,0 +1,187 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RefreshLocker makes sure that only one process refreshes the access token for a key (usually the PSU id or consent)
+// at a time. Lock blocks until the lock is acquired or the context is done and returns a function that releases it.
+type RefreshLocker interface {
+	Lock(ctx context.Context, key string) (unlock func() error, err error)
+}
+
+// MemoryRefreshLocker is a RefreshLocker that coordinates refreshes between goroutines in a single process. The zero
+// value is ready to use.
+type MemoryRefreshLocker struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// Lock acquires the lock for the key
+func (l *MemoryRefreshLocker) Lock(ctx context.Context, key string) (func() error, error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]chan struct{})
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		l.locks[key] = lock
+	}
+	l.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() error {
+			<-lock
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// FileRefreshLocker is a RefreshLocker that uses lock files in a directory shared by all replicas, for example a
+// mounted volume. Each lock file holds a token that identifies its owner. A lock file older than StaleAfter is
+// assumed to belong to a crashed process and is taken over, StaleAfter must therefore be longer than a refresh.
+type FileRefreshLocker struct {
+	Dir          string
+	PollInterval time.Duration // Defaults to 100 milliseconds
+	StaleAfter   time.Duration // Defaults to 1 minute
+}
+
+// Lock acquires the lock for the key by exclusively creating a lock file. The returned function only removes the lock
+// file if it is still owned by the caller, it returns an error if the lock was taken over by another process.
+func (l *FileRefreshLocker) Lock(ctx context.Context, key string) (func() error, error) {
+	pollInterval := l.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 100 * time.Millisecond
+	}
+	staleAfter := l.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = time.Minute
+	}
+
+	lockFile := filepath.Join(l.Dir, url.PathEscape(key)+".lock")
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		file, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = file.WriteString(token)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockFile)
+				return nil, err
+			}
+			return func() error {
+				return unlockFile(lockFile, token)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("could not create lock file: %w", err)
+		}
+
+		if removeStaleLock(lockFile, token, staleAfter) {
+			continue
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// lockToken returns a token that identifies the owner of a lock file across hosts and processes
+func lockToken() (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%x", host, os.Getpid(), random), nil
+}
+
+// removeStaleLock removes the lock file if it is older than staleAfter and reports if the lock should be retried right
+// away. The file is first moved to a name of its own with an atomic rename, so only one replica can take over a stale
+// lock. If another replica replaced the stale lock with a fresh one before the rename, the fresh lock is moved back.
+func removeStaleLock(lockFile string, token string, staleAfter time.Duration) bool {
+	info, err := os.Stat(lockFile)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	if time.Since(info.ModTime()) <= staleAfter {
+		return false
+	}
+
+	moved := lockFile + "." + token + ".stale"
+	if err := os.Rename(lockFile, moved); err != nil {
+		return os.IsNotExist(err)
+	}
+	if info, err := os.Stat(moved); err == nil && time.Since(info.ModTime()) <= staleAfter {
+		os.Link(moved, lockFile)
+	}
+	os.Remove(moved)
+	return true
+}
+
+// unlockFile removes the lock file if it still holds the token. The file is moved away before it is read so that a
+// replica taking over the lock at the same time is not affected, a lock owned by another replica is moved back.
+func unlockFile(lockFile string, token string) error {
+	moved := lockFile + "." + token + ".unlock"
+	if err := os.Rename(lockFile, moved); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("lock file %s was removed by another process", lockFile)
+		}
+		return err
+	}
+	defer os.Remove(moved)
+
+	content, err := os.ReadFile(moved)
+	if err != nil {
+		return err
+	}
+	if string(content) != token {
+		if err := os.Link(moved, lockFile); err != nil {
+			return err
+		}
+		return fmt.Errorf("lock file %s was taken over by another process", lockFile)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testRefreshLocker(t *testing.T, locker RefreshLocker) {
+	unlock, err := locker.Lock(context.Background(), "193805010844")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := locker.Lock(ctx, "193805010844"); err == nil {
+		t.Errorf("Lock was incorrect, acquired a lock that is already held.")
+	}
+
+	// Other keys are not affected
+	unlockOther, err := locker.Lock(context.Background(), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlockOther()
+
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = locker.Lock(context.Background(), "193805010844")
+	if err != nil {
+		t.Fatalf("Lock was incorrect, could not acquire a released lock: %v", err)
+	}
+	unlock()
+}
+
+func TestMemoryRefreshLocker(t *testing.T) {
+	testRefreshLocker(t, &MemoryRefreshLocker{})
+}
+
+func TestFileRefreshLocker(t *testing.T) {
+	testRefreshLocker(t, &FileRefreshLocker{Dir: t.TempDir(), PollInterval: 5 * time.Millisecond})
+}
+
+func TestFileRefreshLockerStale(t *testing.T) {
+	dir := t.TempDir()
+	lockFile := filepath.Join(dir, "193805010844.lock")
+	if err := os.WriteFile(lockFile, []byte("crashed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replicas that find the same stale lock must not hold the lock at the same time
+	var mu sync.Mutex
+	holders, maxHolders := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locker := &FileRefreshLocker{Dir: dir, PollInterval: time.Millisecond, StaleAfter: time.Minute}
+			unlock, err := locker.Lock(context.Background(), "193805010844")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			holders++
+			if holders > maxHolders {
+				maxHolders = holders
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			if err := unlock(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxHolders != 1 {
+		t.Errorf("stale lock takeover was incorrect, got: %d holders, want: 1.", maxHolders)
+	}
+}
+
+func TestFileRefreshLockerUnlockAfterTakeover(t *testing.T) {
+	dir := t.TempDir()
+	lockFile := filepath.Join(dir, "193805010844.lock")
+	locker := &FileRefreshLocker{Dir: dir, PollInterval: time.Millisecond, StaleAfter: time.Minute}
+
+	unlockFirst, err := locker.Lock(context.Background(), "193805010844")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlockSecond, err := locker.Lock(context.Background(), "193805010844")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first owner must not remove the lock that was taken over
+	if err := unlockFirst(); err == nil {
+		t.Errorf("unlock was incorrect, released a lock that was taken over.")
+	}
+	if _, err := os.Stat(lockFile); err != nil {
+		t.Errorf("unlock was incorrect, removed the lock of another owner: %v", err)
+	}
+	if err := unlockSecond(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lockFile); !os.IsNotExist(err) {
+		t.Errorf("unlock was incorrect, the lock file still exists.")
+	}
+}
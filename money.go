@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when doing arithmetic on amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// currencyMinorUnits contains the ISO 4217 currencies that do not use 2 minor units
+var currencyMinorUnits = map[string]int32{
+	"BHD": 3, "BIF": 0, "CLF": 4, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KMF": 0,
+	"KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "UYW": 4, "VND": 0,
+	"VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// CurrencyMinorUnits returns the number of minor units (decimals) of an ISO 4217 currency code, currencies not in the
+// list of exceptions use 2 minor units
+func CurrencyMinorUnits(currency string) int32 {
+	if units, ok := currencyMinorUnits[strings.ToUpper(currency)]; ok {
+		return units
+	}
+	return 2
+}
+
+// Money is an amount in an ISO 4217 currency. Arithmetic between different currencies returns ErrCurrencyMismatch.
+type Money struct {
+	Amount   Decimal
+	Currency string
+}
+
+// NewMoney creates Money from an amount and a currency code
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// ParseMoney parses an amount in the format returned by the API, such as "1234.50", along with the currency code
+func ParseMoney(amount string, currency string) (Money, error) {
+	if err := validateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(d, currency), nil
+}
+
+// MinorUnits returns the number of minor units of the currency
+func (m Money) MinorUnits() int32 {
+	return CurrencyMinorUnits(m.Currency)
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: m.Currency}, nil
+}
+
+// Cmp compares two amounts in the same currency and returns -1, 0 or 1
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Amount: m.Amount.Neg(), Currency: m.Currency}
+}
+
+// Abs returns the absolute amount
+func (m Money) Abs() Money {
+	return Money{Amount: m.Amount.Abs(), Currency: m.Currency}
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the amount
+func (m Money) Sign() int {
+	return m.Amount.Sign()
+}
+
+// Convert multiplies the amount with an exchange rate and returns it in the other currency rounded to its minor units
+func (m Money) Convert(rate Decimal, currency string) Money {
+	return Money{Amount: m.Amount.Mul(rate).Round(CurrencyMinorUnits(currency)), Currency: strings.ToUpper(currency)}
+}
+
+// Round rounds the amount to the minor units of the currency
+func (m Money) Round() Money {
+	return Money{Amount: m.Amount.Round(m.MinorUnits()), Currency: m.Currency}
+}
+
+// MinorUnitAmount returns the amount in minor units, for example 1234.50 SEK is 123450. An error is returned if the
+// amount has more decimals than the currency allows.
+func (m Money) MinorUnitAmount() (*big.Int, error) {
+	rounded := m.Amount.Round(m.MinorUnits())
+	if rounded.Cmp(m.Amount) != 0 {
+		return nil, fmt.Errorf("%s has more than %d decimals", m, m.MinorUnits())
+	}
+	return new(big.Int).Set(rounded.int()), nil
+}
+
+// String returns the amount followed by the currency, for example "1234.50 SEK"
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// MarshalJSON encodes the money as an object with the amount as a string the same way the API does
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   Decimal `json:"amount"`
+		Currency string  `json:"currency"`
+	}{m.Amount, m.Currency})
+}
+
+// UnmarshalJSON decodes money from an object with an amount and a currency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var money struct {
+		Amount   Decimal `json:"amount"`
+		Currency string  `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &money); err != nil {
+		return err
+	}
+	if err := validateCurrency(money.Currency); err != nil {
+		return err
+	}
+	*m = NewMoney(money.Amount, money.Currency)
+	return nil
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if !strings.EqualFold(m.Currency, other.Currency) {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+func validateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return fmt.Errorf("invalid currency code %q", currency)
+	}
+	for _, r := range currency {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return fmt.Errorf("invalid currency code %q", currency)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDecimalJSONRoundTrip(t *testing.T) {
+	for _, value := range []string{`"1234.50"`, `"-0.01"`, `"100"`, `"0.000"`, `"12345678901234567890.123456789"`} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(value), &d); err != nil {
+			t.Fatal(err)
+		}
+		content, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != value {
+			t.Errorf("Decimal round trip was incorrect, got: %s, want: %s.", content, value)
+		}
+	}
+
+	var d Decimal
+	if err := json.Unmarshal([]byte(`""`), &d); err != nil || d.IsSet() {
+		t.Errorf("empty amount was incorrect, got: %v and error %v, want an unset decimal.", d, err)
+	}
+	for _, invalid := range []string{"1.2.3", "abc", "-", "1e5"} {
+		if _, err := ParseDecimal(invalid); err == nil {
+			t.Errorf("ParseDecimal(%s) was incorrect, expected an error.", invalid)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.20")
+
+	tests := []struct {
+		got   Decimal
+		valid string
+	}{
+		{a.Add(b), "0.30"},
+		{a.Sub(b), "-0.10"},
+		{a.Mul(b), "0.020"},
+		{MustParseDecimal("2.345").Round(2), "2.35"},
+		{MustParseDecimal("-2.345").Round(2), "-2.35"},
+		{MustParseDecimal("2.344").Round(2), "2.34"},
+		{MustParseDecimal("5").Round(2), "5.00"},
+		{MustParseDecimal("12.5").Round(-1), "10"},
+		{MustParseDecimal("15").Round(-1), "20"},
+		{MustParseDecimal("-1250.75").Round(-2), "-1300"},
+		{MustParseDecimal("0.4").Round(-1), "0"},
+		{NewDecimal(12345, 2), "123.45"},
+		{MustParseDecimal("108.90").Quo(MustParseDecimal("10.00"), 4), "10.8900"},
+		{MustParseDecimal("59.90").Quo(MustParseDecimal("5.5"), 6), "10.890909"},
+		{MustParseDecimal("-2").Quo(MustParseDecimal("3"), 2), "-0.67"},
+		{MustParseDecimal("1250").Quo(MustParseDecimal("1"), -2), "1300"},
+	}
+	for _, test := range tests {
+		if test.got.String() != test.valid {
+			t.Errorf("Decimal arithmetic was incorrect, got: %s, want: %s.", test.got, test.valid)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	sek, err := ParseMoney("100.50", "SEK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eur, err := ParseMoney("10", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sek.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add was incorrect, got: %v, want: %v.", err, ErrCurrencyMismatch)
+	}
+
+	sum, err := sek.Add(mustParseMoney(t, "0.50", "SEK"))
+	if err != nil || sum.String() != "101.00 SEK" {
+		t.Errorf("Add was incorrect, got: %s and error %v, want: 101.00 SEK.", sum, err)
+	}
+
+	minor, err := sek.MinorUnitAmount()
+	if err != nil || minor.Int64() != 10050 {
+		t.Errorf("MinorUnitAmount was incorrect, got: %v and error %v, want: 10050.", minor, err)
+	}
+	if _, err := mustParseMoney(t, "1.5", "JPY").MinorUnitAmount(); err == nil {
+		t.Errorf("MinorUnitAmount was incorrect, expected an error for 1.5 JPY.")
+	}
+
+	if converted := eur.Convert(MustParseDecimal("10.4567"), "SEK"); converted.String() != "104.57 SEK" {
+		t.Errorf("Convert was incorrect, got: %s, want: 104.57 SEK.", converted)
+	}
+
+	content, err := json.Marshal(sek)
+	if err != nil || string(content) != `{"amount":"100.50","currency":"SEK"}` {
+		t.Errorf("MarshalJSON was incorrect, got: %s and error %v.", content, err)
+	}
+}
+
+func mustParseMoney(t *testing.T, amount string, currency string) Money {
+	m, err := ParseMoney(amount, currency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -23,91 +23,66 @@
 package pis
 
 import (
-	"errors"
-	"github.com/markustenghamn/nordeago"
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
-)
-
-// GetPayments returns a nordeago.Result with pis.PaymentsResponse as the response
-func GetPayments(c *nordeago.Client, country string) (*PaymentsResponse, error) {
-	responseType := &PaymentsResponse{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := getEndpointFromCountry(country)
-
-	response, err := c.GetWithAccessToken(endpoint, nil)
-
-	if err != nil {
-		return responseType, err
-	}
-
-	defer response.Body.Close()
 
-	_, err = c.HandleResponse(response, &result)
+	"github.com/markustenghamn/nordeago"
+)
 
-	return responseType, err
+// GetPayments returns a pis.PaymentsResponse with the payments for the country
+func GetPayments(ctx context.Context, c *nordeago.Client, country string) (*PaymentsResponse, error) {
+	response, _, err := nordeago.Do[PaymentsResponse](ctx, c, http.MethodGet, getEndpointFromCountry(country), nil)
+	return &response, err
 }
 
 // InitiatePayment sends an InitiatePaymentRequest and returns true if the API responds with a 201 created status code
-func InitiatePayment(c *nordeago.Client, country string, request InitiatePaymentRequest, skipAccessControl bool) (bool, error) {
-	endpoint := getEndpointFromCountry(country)
-
-	var headers map[string]string
-	// X-Response-Scenarios header can be set to AuthorizationSkipAccessControl in sandbox environments
-	if skipAccessControl {
-		headers = make(map[string]string)
-		headers["X-Response-Scenarios"] = "AuthorizationSkipAccessControl"
+func InitiatePayment(ctx context.Context, c *nordeago.Client, country string, request InitiatePaymentRequest, skipAccessControl bool) (bool, error) {
+	if err := request.Validate(); err != nil {
+		return false, err
 	}
 
-	response, err := c.PostWithAccessToken(endpoint, request, headers)
+	_, meta, err := nordeago.DoRequest[json.RawMessage](ctx, c, nordeago.Request{
+		Method:   http.MethodPost,
+		Endpoint: getEndpointFromCountry(country),
+		Body:     request,
+		Headers:  skipAccessControlHeaders(skipAccessControl),
+	})
 
 	if err != nil {
 		return false, err
 	}
 
-	defer response.Body.Close()
-
-	if response.StatusCode == http.StatusCreated {
+	if meta.StatusCode == http.StatusCreated {
 		return true, nil
 	}
 
-	err = errors.New("Request returned status " + string(response.StatusCode))
-	return false, err
+	return false, fmt.Errorf("Request returned status %d", meta.StatusCode)
 }
 
-// GetPayment returns a nordeago.Result with pis.Payment as the response
-func GetPayment(c *nordeago.Client, country string, paymentID string, skipAccessControl bool) (*Payment, error) {
-	responseType := &Payment{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := nordeago.ReplaceVariable(getEndpointFromCountry(country)+"/{{paymentId}}", "paymentId", paymentID)
-
-	var headers map[string]string
-	// X-Response-Scenarios header can be set to AuthorizationSkipAccessControl in sandbox environments
-	if skipAccessControl {
-		headers = make(map[string]string)
-		headers["X-Response-Scenarios"] = "AuthorizationSkipAccessControl"
-	}
-
-	response, err := c.GetWithAccessToken(endpoint, headers)
-
+// GetPayment returns the pis.Payment with the specified payment id
+func GetPayment(ctx context.Context, c *nordeago.Client, country string, paymentID string, skipAccessControl bool) (*Payment, error) {
+	endpoint, err := nordeago.NewEndpoint(getEndpointFromCountry(country)+"/{{paymentId}}").With("paymentId", paymentID).Build()
 	if err != nil {
-		return responseType, err
+		return &Payment{}, err
 	}
 
-	defer response.Body.Close()
+	response, _, err := nordeago.DoRequest[Payment](ctx, c, nordeago.Request{
+		Method:   http.MethodGet,
+		Endpoint: endpoint,
+		Headers:  skipAccessControlHeaders(skipAccessControl),
+	})
 
-	_, err = c.HandleResponse(response, &result)
-
-	return responseType, err
+	return &response, err
 }
 
-// ConfirmPayment returns a nordeago.Result with pis.Payment as the response
-func ConfirmPayment(c *nordeago.Client, country string, paymentID string, responseScenario string) (*Payment, error) {
-	responseType := &Payment{}
-	result := nordeago.Result{Response: responseType}
-
-	endpoint := nordeago.ReplaceVariable(getEndpointFromCountry(country)+"/{{paymentId}}/confirm", "paymentId", paymentID)
+// ConfirmPayment confirms the payment and returns the updated pis.Payment
+func ConfirmPayment(ctx context.Context, c *nordeago.Client, country string, paymentID string, responseScenario string) (*Payment, error) {
+	endpoint, err := nordeago.NewEndpoint(getEndpointFromCountry(country)+"/{{paymentId}}/confirm").With("paymentId", paymentID).Build()
+	if err != nil {
+		return &Payment{}, err
+	}
 
 	// X-Response-Scenarios header can be set to AuthorizationSkipAccessControl, PaymentSigningExpires, PaymentMissingFunds or PaymentOnHold in sandbox environments
 	var headers map[string]string
@@ -116,17 +91,24 @@ func ConfirmPayment(c *nordeago.Client, country string, paymentID string, respon
 		headers["X-Response-Scenarios"] = responseScenario
 	}
 
-	response, err := c.PutWithAccessToken(endpoint, headers)
-
-	if err != nil {
-		return responseType, err
-	}
-
-	defer response.Body.Close()
+	response, _, err := nordeago.DoRequest[Payment](ctx, c, nordeago.Request{
+		Method:   http.MethodPut,
+		Endpoint: endpoint,
+		Headers:  headers,
+	})
 
-	_, err = c.HandleResponse(response, &result)
+	return &response, err
+}
 
-	return responseType, err
+// skipAccessControlHeaders sets the X-Response-Scenarios header to AuthorizationSkipAccessControl which can be used in
+// sandbox environments
+func skipAccessControlHeaders(skipAccessControl bool) map[string]string {
+	if !skipAccessControl {
+		return nil
+	}
+	headers := make(map[string]string)
+	headers["X-Response-Scenarios"] = "AuthorizationSkipAccessControl"
+	return headers
 }
 
 func getEndpointFromCountry(country string) string {
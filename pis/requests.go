@@ -1,10 +1,38 @@
 package pis
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/markustenghamn/nordeago"
+)
+
 // InitiatePaymentRequest represents the parameters for creating a payment
 type InitiatePaymentRequest struct {
-	Amount     string   `json:"amount,omitempty"`
-	Currency   string   `json:"currency"`
-	Creditor   Creditor `json:"creditor"`
-	Debtor     Debtor   `json:"debtor"`
-	ExternalID string   `json:"externalId,omitempty"`
+	Amount     nordeago.Decimal `json:"amount,omitzero"`
+	Currency   string           `json:"currency"`
+	Creditor   Creditor         `json:"creditor"`
+	Debtor     Debtor           `json:"debtor"`
+	ExternalID string           `json:"externalId,omitempty"`
+}
+
+// Money returns the amount of the payment in its currency
+func (r InitiatePaymentRequest) Money() nordeago.Money {
+	return nordeago.NewMoney(r.Amount, r.Currency)
+}
+
+// Validate validates the creditor and debtor account numbers of the payment. The creditor account is required, the
+// debtor account may be left out when the debtor is identified by its account id.
+func (r InitiatePaymentRequest) Validate() error {
+	if len(strings.TrimSpace(r.Creditor.Account.Value)) == 0 {
+		return errors.New("creditor account is required")
+	}
+	if err := r.Creditor.Account.Validate(); err != nil {
+		return fmt.Errorf("invalid creditor account: %w", err)
+	}
+	if err := r.Debtor.Account.Validate(); err != nil {
+		return fmt.Errorf("invalid debtor account: %w", err)
+	}
+	return nil
 }
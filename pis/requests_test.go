@@ -0,0 +1,59 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package pis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/markustenghamn/nordeago"
+)
+
+func TestInitiatePaymentRequestValidate(t *testing.T) {
+	request := InitiatePaymentRequest{
+		Amount:   nordeago.MustParseDecimal("100.00"),
+		Currency: "SEK",
+		Creditor: Creditor{Account: Account{Type: "BBAN_SE", Currency: "SEK", Value: "41770042136"}},
+		Debtor:   Debtor{AccountID: "SE4550000000058398257466-SEK"},
+	}
+	if err := request.Validate(); err != nil {
+		t.Errorf("Validate was incorrect, got: %s, want: nil.", err)
+	}
+
+	request.Debtor.Account = Account{Type: "IBAN", Currency: "SEK", Value: "SE4550000000058398257467"}
+	if err := request.Validate(); err == nil {
+		t.Errorf("Validate was incorrect, got: nil, want: invalid debtor account.")
+	}
+
+	request.Debtor.Account = Account{}
+	request.Creditor.Account.Value = ""
+	if err := request.Validate(); err == nil {
+		t.Errorf("Validate was incorrect, got: nil, want: creditor account is required.")
+	}
+
+	request.Debtor.Account = Account{Type: "BGNR", Currency: "SEK", Value: "5555-5555"}
+	request.Creditor.Account.Value = "41770042137"
+	if _, err := InitiatePayment(context.Background(), &nordeago.Client{}, "SE", request, false); err == nil {
+		t.Errorf("InitiatePayment was incorrect, got: nil, want: invalid creditor account.")
+	}
+}
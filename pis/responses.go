@@ -1,6 +1,9 @@
 package pis
 
-import "github.com/markustenghamn/nordeago"
+import (
+	"github.com/markustenghamn/nordeago"
+	"github.com/markustenghamn/nordeago/iban"
+)
 
 // PaymentsResponse is returned when listing multiple payments via the GetPayments method
 type PaymentsResponse struct {
@@ -9,15 +12,20 @@ type PaymentsResponse struct {
 
 // Payment represents the structure of a payment returned via the api
 type Payment struct {
-	ID            string          `json:"_id"`
-	Links         []nordeago.Link `json:"_links,omitempty"`
-	Amount        string          `json:"amount,omitempty"`
-	Currency      string          `json:"currency"`
-	Creditor      Creditor        `json:"creditor"`
-	Debtor        Debtor          `json:"debtor"`
-	ExternalID    string          `json:"externalId,omitempty"`
-	PaymentStatus string          `json:"paymentStatus,omitempty"` // PendingConfirmation, PendingUserApproval, OnHold, Confirmed, Rejected, Paid, InsufficientFunds, LimitExceeded, UserApprovalFailed, UserApprovalTimeout, UserApprovalCancelled, Unknown
-	Timestamp     string          `json:"timestamp"`
+	ID            string             `json:"_id"`
+	Links         []nordeago.Link    `json:"_links,omitempty"`
+	Amount        nordeago.Decimal   `json:"amount,omitzero"`
+	Currency      string             `json:"currency"`
+	Creditor      Creditor           `json:"creditor"`
+	Debtor        Debtor             `json:"debtor"`
+	ExternalID    string             `json:"externalId,omitempty"`
+	PaymentStatus string             `json:"paymentStatus,omitempty"` // PendingConfirmation, PendingUserApproval, OnHold, Confirmed, Rejected, Paid, InsufficientFunds, LimitExceeded, UserApprovalFailed, UserApprovalTimeout, UserApprovalCancelled, Unknown
+	Timestamp     nordeago.Timestamp `json:"timestamp"`
+}
+
+// Money returns the amount of the payment in its currency
+func (p Payment) Money() nordeago.Money {
+	return nordeago.NewMoney(p.Amount, p.Currency)
 }
 
 // Creditor is part of the payment type
@@ -35,6 +43,15 @@ type Account struct {
 	Value    string `json:"value"`
 }
 
+// Validate validates the account number according to its type, see iban.ValidateAccount. An account without a value is
+// valid since the debtor can be identified by the account id alone.
+func (a Account) Validate() error {
+	if len(a.Value) == 0 {
+		return nil
+	}
+	return iban.ValidateAccount(a.Type, a.Value)
+}
+
 // CreditorReference represents an invoice number or reference id
 type CreditorReference struct {
 	Type  string `json:"_type"` // RF, INVOICE
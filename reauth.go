@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ReauthHandler refreshes the access token of a client or restarts the authentication flow when a request is
+// rejected with a 401 status code or an expired consent. The handler should store the new token with
+// c.SetAccessToken before returning. Requests made by the handler are never retried as long as they are made with the
+// context passed to Reauthenticate.
+type ReauthHandler interface {
+	Reauthenticate(ctx context.Context, c *Client) error
+}
+
+// ReauthHandlerFunc allows a regular function to be used as a ReauthHandler
+type ReauthHandlerFunc func(ctx context.Context, c *Client) error
+
+// Reauthenticate calls f(ctx, c)
+func (f ReauthHandlerFunc) Reauthenticate(ctx context.Context, c *Client) error {
+	return f(ctx, c)
+}
+
+// reauthContextKey marks the context passed to a ReauthHandler so that requests made by the handler are not retried
+type reauthContextKey struct{}
+
+// reauthCall is a reauthentication in progress, requests that are rejected while it runs wait for its result
+type reauthCall struct {
+	done chan struct{}
+	err  error
+}
+
+// GetAccessToken returns the access token of the client. Use it instead of reading AccessToken directly when requests
+// are made concurrently, as the ReauthHandler can replace the token at any time.
+func (c *Client) GetAccessToken() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.AccessToken
+}
+
+// SetAccessToken replaces the access token of the client, it is safe to call while other requests are being made
+func (c *Client) SetAccessToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.AccessToken = token
+}
+
+// requestWithAccessToken sets the access token headers and makes the request, if the request is rejected and a
+// ReauthHandler is set the client is reauthenticated and the request is replayed once with the new access token
+func (c *Client) requestWithAccessToken(ctx context.Context, requestType string, endpoint string, body []byte, headers map[string]string) (*http.Response, error) {
+	headers = c.setAccessTokenHeaders(headers)
+	rejected := headers["Authorization"]
+
+	response, err := c.request(ctx, requestType, endpoint, bodyReader(body), headers)
+
+	if err != nil || c.ReauthHandler == nil || ctx.Value(reauthContextKey{}) != nil || !requiresReauth(response) {
+		return response, err
+	}
+	response.Body.Close()
+
+	if err := c.reauthenticate(ctx, rejected); err != nil {
+		return nil, fmt.Errorf("reauthentication failed: %w", err)
+	}
+
+	headers = c.setAccessTokenHeaders(headers)
+
+	return c.request(ctx, requestType, endpoint, bodyReader(body), headers)
+}
+
+// reauthenticate calls the ReauthHandler after a request was rejected with the authorization header rejected. Only one
+// reauthentication runs at a time per client, other rejected requests wait for it and share its result. Nothing is
+// done if the access token was already replaced after the rejected request was sent.
+func (c *Client) reauthenticate(ctx context.Context, rejected string) error {
+	c.reauthMu.Lock()
+	if call := c.reauthCall; call != nil {
+		c.reauthMu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if BearerAuthHeader(c.GetAccessToken()) != rejected {
+		c.reauthMu.Unlock()
+		return nil
+	}
+	call := &reauthCall{done: make(chan struct{})}
+	c.reauthCall = call
+	c.reauthMu.Unlock()
+
+	defer func() {
+		c.reauthMu.Lock()
+		c.reauthCall = nil
+		c.reauthMu.Unlock()
+		close(call.done)
+	}()
+
+	call.err = c.ReauthHandler.Reauthenticate(context.WithValue(ctx, reauthContextKey{}, true), c)
+	return call.err
+}
+
+// requiresReauth checks if a response was rejected because of an invalid access token or an expired consent. The
+// response body is read when checking for an expired consent and is replaced so that it can still be decoded.
+func requiresReauth(response *http.Response) bool {
+	if response.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	if response.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	content, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(content))
+	if err != nil {
+		return false
+	}
+
+	result := Result{}
+	if json.Unmarshal(content, &result) != nil {
+		return false
+	}
+	for _, failure := range result.Error.Failures {
+		if isConsentExpired(failure.Code) || isConsentExpired(failure.Description) {
+			return true
+		}
+	}
+	return isConsentExpired(result.Error.MoreInformation)
+}
+
+func isConsentExpired(text string) bool {
+	text = strings.ToLower(text)
+	return strings.Contains(text, "consent") && strings.Contains(text, "expired")
+}
+
+func bodyReader(body []byte) io.Reader {
+	if body == nil {
+		return nil
+	}
+	return bytes.NewReader(body)
+}
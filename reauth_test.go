@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func testClient(server *httptest.Server) *Client {
+	c := InitClient("client-id", "client-secret", "https://httpbin.org/get")
+	c.Protocol = "http://"
+	c.BaseURL = strings.TrimPrefix(server.URL, "http://")
+	return &c
+}
+
+func TestReauthHandlerReplaysRequestOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	c.AccessToken = "old-token"
+	calls := 0
+	c.ReauthHandler = ReauthHandlerFunc(func(ctx context.Context, c *Client) error {
+		calls++
+		c.SetAccessToken("new-token")
+		return nil
+	})
+
+	response, err := c.GetWithAccessToken("/accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || calls != 1 {
+		t.Errorf("reauth was incorrect, got: status %d and %d calls, want: status 200 and 1 call.", response.StatusCode, calls)
+	}
+}
+
+func TestReauthHandlerDoesNotLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	calls := 0
+	c.ReauthHandler = ReauthHandlerFunc(func(ctx context.Context, c *Client) error {
+		calls++
+		// Requests made from the handler must not trigger the handler again
+		response, err := c.requestWithAccessToken(ctx, http.MethodGet, "/assets", nil, nil)
+		if err != nil {
+			return err
+		}
+		response.Body.Close()
+		return nil
+	})
+
+	response, err := c.GetWithAccessToken("/accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized || calls != 1 {
+		t.Errorf("reauth was incorrect, got: status %d and %d calls, want: status 401 and 1 call.", response.StatusCode, calls)
+	}
+}
+
+func TestReauthHandlerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"httpCode":403,"failures":[{"code":"error.consent.expired","description":"Consent has expired"}]}}`))
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	c.ReauthHandler = ReauthHandlerFunc(func(ctx context.Context, c *Client) error {
+		return errors.New("user declined")
+	})
+
+	_, err := c.PostWithAccessToken("/payments/sepa", map[string]string{"amount": "1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "user declined") {
+		t.Errorf("reauth error was incorrect, got: %v, want: reauthentication failed: user declined.", err)
+	}
+}
+
+func TestReauthHandlerConcurrentRequests(t *testing.T) {
+	const requests = 8
+
+	var rejected sync.WaitGroup
+	rejected.Add(requests)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			rejected.Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	c.AccessToken = "old-token"
+	var calls int32
+	c.ReauthHandler = ReauthHandlerFunc(func(ctx context.Context, c *Client) error {
+		atomic.AddInt32(&calls, 1)
+		// Keep the reauthentication running until every request has been rejected
+		rejected.Wait()
+		c.SetAccessToken("new-token")
+		return nil
+	})
+
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			response, err := c.GetWithAccessToken("/accounts", nil)
+			if err != nil {
+				t.Error(err)
+				statuses <- 0
+				return
+			}
+			response.Body.Close()
+			statuses <- response.StatusCode
+		}()
+	}
+	for i := 0; i < requests; i++ {
+		if status := <-statuses; status != http.StatusOK {
+			t.Errorf("replayed request was incorrect, got: status %d, want: status 200.", status)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("reauth was incorrect, got: %d calls, want: 1 call.", calls)
+	}
+}
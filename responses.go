@@ -11,7 +11,7 @@ type Result struct {
 // a creation datetime and message ID that can be used for debugging purposes
 type GroupHeader struct {
 	MessageIdentification string              `json:"messageIdentification"`
-	CreationDateTime      string              `json:"creationDateTime"`
+	CreationDateTime      Timestamp           `json:"creationDateTime"`
 	HTTPCode              int64               `json:"httpCode"`
 	MessagePagination     []MessagePagination `json:"messagePagination,omitempty"`
 }
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTokenNotFound is returned by a TokenStore when no token has been saved for the key
+var ErrTokenNotFound = errors.New("token not found")
+
+// Token is an access token along with the refresh token and the time it expires. Scopes, Accounts and PsuID can be
+// stored with the token and are used by Claims when the access token is opaque.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	Accounts     []string  `json:"accounts,omitempty"`
+	PsuID        string    `json:"psu_id,omitempty"`
+}
+
+// Expired returns true if the token has an expiry time that has passed
+func (t Token) Expired() bool {
+	return !t.Expiry.IsZero() && time.Now().After(t.Expiry)
+}
+
+// TokenStore persists tokens so that they can be shared between replicas using the same consent
+type TokenStore interface {
+	Load(key string) (Token, error)
+	Save(key string, token Token) error
+}
+
+// MemoryTokenStore is a TokenStore that keeps tokens in memory. The zero value is ready to use.
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+// Load returns the token for the key or ErrTokenNotFound
+func (s *MemoryTokenStore) Load(key string) (Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return token, ErrTokenNotFound
+	}
+	return token, nil
+}
+
+// Save stores the token for the key
+func (s *MemoryTokenStore) Save(key string, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = make(map[string]Token)
+	}
+	s.tokens[key] = token
+	return nil
+}
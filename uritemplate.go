@@ -0,0 +1,235 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// templateOperator describes how the variables of an expression are expanded, see RFC 6570 appendix A
+type templateOperator struct {
+	first         string
+	separator     string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+var templateOperators = map[byte]templateOperator{
+	'+': {first: "", separator: ",", allowReserved: true},
+	'#': {first: "#", separator: ",", allowReserved: true},
+	'.': {first: ".", separator: "."},
+	'/': {first: "/", separator: "/"},
+	';': {first: ";", separator: ";", named: true},
+	'?': {first: "?", separator: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", separator: "&", named: true, ifEmpty: "="},
+}
+
+// ExpandURITemplate expands a RFC 6570 URI template up to level 4. Variables can be strings, []string or
+// map[string]string, undefined variables are removed from the result.
+func ExpandURITemplate(template string, variables map[string]interface{}) (string, error) {
+	var result strings.Builder
+
+	for len(template) > 0 {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			result.WriteString(template)
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unclosed expression in uri template %s", template)
+		}
+		end += start
+
+		result.WriteString(template[:start])
+		expanded, err := expandExpression(template[start+1:end], variables)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(expanded)
+
+		template = template[end+1:]
+	}
+
+	return result.String(), nil
+}
+
+func expandExpression(expression string, variables map[string]interface{}) (string, error) {
+	if len(expression) == 0 {
+		return "", fmt.Errorf("empty expression in uri template")
+	}
+
+	operator := templateOperator{separator: ","}
+	if op, ok := templateOperators[expression[0]]; ok {
+		operator = op
+		expression = expression[1:]
+	}
+
+	var parts []string
+	for _, spec := range strings.Split(expression, ",") {
+		name, explode, prefix, err := parseVarSpec(spec)
+		if err != nil {
+			return "", err
+		}
+
+		switch value := variables[name].(type) {
+		case nil:
+		case string:
+			parts = append(parts, expandString(operator, name, value, prefix))
+		case []string:
+			if len(value) > 0 {
+				parts = append(parts, expandList(operator, name, value, explode))
+			}
+		case map[string]string:
+			if len(value) > 0 {
+				parts = append(parts, expandMap(operator, name, value, explode))
+			}
+		default:
+			return "", fmt.Errorf("unsupported type %T for uri template variable %s", value, name)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+
+	return operator.first + strings.Join(parts, operator.separator), nil
+}
+
+func parseVarSpec(spec string) (name string, explode bool, prefix int, err error) {
+	if strings.HasSuffix(spec, "*") {
+		return spec[:len(spec)-1], true, 0, nil
+	}
+	if i := strings.IndexByte(spec, ':'); i >= 0 {
+		prefix, err = strconv.Atoi(spec[i+1:])
+		if err != nil || prefix <= 0 || prefix >= 10000 {
+			return "", false, 0, fmt.Errorf("invalid prefix in uri template variable %s", spec)
+		}
+		spec = spec[:i]
+	}
+	if len(spec) == 0 {
+		return "", false, 0, fmt.Errorf("empty variable name in uri template")
+	}
+	return spec, false, prefix, nil
+}
+
+func expandString(operator templateOperator, name string, value string, prefix int) string {
+	if prefix > 0 && utf8.RuneCountInString(value) > prefix {
+		value = string([]rune(value)[:prefix])
+	}
+	value = encodeTemplateValue(value, operator.allowReserved)
+	if !operator.named {
+		return value
+	}
+	if len(value) == 0 {
+		return name + operator.ifEmpty
+	}
+	return name + "=" + value
+}
+
+func expandList(operator templateOperator, name string, values []string, explode bool) string {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		encoded[i] = encodeTemplateValue(value, operator.allowReserved)
+		if explode && operator.named {
+			if len(encoded[i]) == 0 {
+				encoded[i] = name + operator.ifEmpty
+			} else {
+				encoded[i] = name + "=" + encoded[i]
+			}
+		}
+	}
+
+	if explode {
+		return strings.Join(encoded, operator.separator)
+	}
+	if operator.named {
+		return name + "=" + strings.Join(encoded, ",")
+	}
+	return strings.Join(encoded, ",")
+}
+
+func expandMap(operator templateOperator, name string, values map[string]string, explode bool) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var encoded []string
+	for _, key := range keys {
+		encodedKey := encodeTemplateValue(key, operator.allowReserved)
+		encodedValue := encodeTemplateValue(values[key], operator.allowReserved)
+		if explode {
+			if operator.named && len(encodedValue) == 0 {
+				encoded = append(encoded, encodedKey+operator.ifEmpty)
+			} else {
+				encoded = append(encoded, encodedKey+"="+encodedValue)
+			}
+		} else {
+			encoded = append(encoded, encodedKey, encodedValue)
+		}
+	}
+
+	if explode {
+		return strings.Join(encoded, operator.separator)
+	}
+	if operator.named {
+		return name + "=" + strings.Join(encoded, ",")
+	}
+	return strings.Join(encoded, ",")
+}
+
+// encodeTemplateValue percent encodes everything except unreserved characters, reserved characters and existing
+// percent encoded triplets are kept when allowReserved is true
+func encodeTemplateValue(value string, allowReserved bool) string {
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		switch {
+		case isUnreserved(b):
+			encoded.WriteByte(b)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", b) >= 0:
+			encoded.WriteByte(b)
+		case allowReserved && b == '%' && i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]):
+			encoded.WriteString(value[i : i+3])
+			i += 2
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func isUnreserved(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+func isHex(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'f' || 'A' <= b && b <= 'F'
+}
@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2018 Markus Tenghamn
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nordeago
+
+import "testing"
+
+func TestExpandURITemplate(t *testing.T) {
+	variables := map[string]interface{}{
+		"var":   "value",
+		"hello": "Hello World!",
+		"path":  "/foo/bar",
+		"empty": "",
+		"list":  []string{"red", "green", "blue"},
+		"keys":  map[string]string{"semi": ";", "dot": ".", "comma": ","},
+	}
+
+	// Examples from RFC 6570
+	tests := []struct {
+		template string
+		valid    string
+	}{
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"{#path}", "#/foo/bar"},
+		{"X{.var}", "X.value"},
+		{"{/var,undefined}", "/value"},
+		{"{;empty}", ";empty"},
+		{"{?var,empty}", "?var=value&empty="},
+		{"?fixed=yes{&var}", "?fixed=yes&var=value"},
+		{"{var:3}", "val"},
+		{"{list}", "red,green,blue"},
+		{"{/list*}", "/red/green/blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+		{"/payments/sepa{/paymentId}/confirm", "/payments/sepa/confirm"},
+	}
+
+	for _, test := range tests {
+		expanded, err := ExpandURITemplate(test.template, variables)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expanded != test.valid {
+			t.Errorf("ExpandURITemplate(%s) was incorrect, got: %s, want: %s.", test.template, expanded, test.valid)
+		}
+	}
+
+	if _, err := ExpandURITemplate("{unclosed", variables); err == nil {
+		t.Errorf("ExpandURITemplate was incorrect, expected an error for an unclosed expression.")
+	}
+}